@@ -0,0 +1,158 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"math"
+	"sort"
+)
+
+// Centrality represents a measure of how central a node is in a graph
+type Centrality string
+
+/*
+Centrality constants. Degree counts the neighbours of a node, Strength sums up the weight of its edges, Eigenvector
+scores nodes higher when they are linked to high scoring nodes (normalized so that the top node scores 1). Edges are
+taken as undirected, and parallel edges of different kinds count as a single link for Degree.
+*/
+const (
+	Degree      Centrality = "degree"
+	Strength    Centrality = "strength"
+	Eigenvector Centrality = "eigenvector"
+)
+
+const (
+	eigenvectorIterations = 100
+	eigenvectorTolerance  = 1e-9
+)
+
+// NodeScore models the position of a node in a centrality ranking
+type NodeScore struct {
+	Rank  int
+	Node  *Node
+	Score float64
+}
+
+/*
+Rank ranks the nodes of the graph by centrality, and keeps the first size (all if size <= 0). Nodes with the same score
+share the same rank, and are ordered by id.
+*/
+func (g *Graph) Rank(centrality Centrality, size int) []*NodeScore {
+
+	var scores map[string]float64
+	switch centrality {
+	case Strength:
+		scores = g.strength()
+	case Eigenvector:
+		scores = g.eigenvector()
+	default:
+		scores = g.degree()
+	}
+
+	ranking := make([]*NodeScore, 0, len(g.nodeIDs))
+	for _, id := range g.nodeIDs {
+		ranking = append(ranking, &NodeScore{Node: g.nodes[id], Score: scores[id]})
+	}
+	sort.SliceStable(ranking, func(i, j int) bool {
+		if ranking[i].Score != ranking[j].Score {
+			return ranking[i].Score > ranking[j].Score
+		}
+		return ranking[i].Node.ID < ranking[j].Node.ID
+	})
+	if size > 0 && len(ranking) > size {
+		ranking = ranking[:size]
+	}
+	for i, score := range ranking {
+		score.Rank = i + 1
+		if i > 0 && score.Score == ranking[i-1].Score {
+			score.Rank = ranking[i-1].Rank
+		}
+	}
+	return ranking
+}
+
+// neighbours returns the total weight of the edges between every pair of linked nodes, in both directions
+func (g *Graph) neighbours() map[string]map[string]float64 {
+
+	adjacency := make(map[string]map[string]float64, len(g.nodeIDs))
+	link := func(a, b string, w float64) {
+		if adjacency[a] == nil {
+			adjacency[a] = make(map[string]float64)
+		}
+		adjacency[a][b] += w
+	}
+	for _, key := range g.edgeKeys {
+		edge := g.edges[key]
+		if edge.From == edge.To {
+			continue
+		}
+		link(edge.From, edge.To, edge.Weight)
+		link(edge.To, edge.From, edge.Weight)
+	}
+	return adjacency
+}
+
+func (g *Graph) degree() map[string]float64 {
+
+	scores := make(map[string]float64, len(g.nodeIDs))
+	for id, neighbours := range g.neighbours() {
+		scores[id] = float64(len(neighbours))
+	}
+	return scores
+}
+
+func (g *Graph) strength() map[string]float64 {
+
+	scores := make(map[string]float64, len(g.nodeIDs))
+	for id, neighbours := range g.neighbours() {
+		for _, w := range neighbours {
+			scores[id] += w
+		}
+	}
+	return scores
+}
+
+// eigenvector uses power iteration; adding the previous scores at every step avoids oscillations on bipartite graphs.
+// Edges are visited in order, so that ties are not broken by rounding errors.
+func (g *Graph) eigenvector() map[string]float64 {
+
+	scores := make(map[string]float64, len(g.nodeIDs))
+	for _, id := range g.nodeIDs {
+		scores[id] = 1
+	}
+	for i := 0; i < eigenvectorIterations; i++ {
+		next := make(map[string]float64, len(scores))
+		for _, id := range g.nodeIDs {
+			next[id] = scores[id]
+		}
+		for _, key := range g.edgeKeys {
+			edge := g.edges[key]
+			if edge.From == edge.To {
+				continue
+			}
+			next[edge.From] += edge.Weight * scores[edge.To]
+			next[edge.To] += edge.Weight * scores[edge.From]
+		}
+		max := 0.0
+		for _, id := range g.nodeIDs {
+			max = math.Max(max, next[id])
+		}
+		delta := 0.0
+		for _, id := range g.nodeIDs {
+			next[id] /= max
+			delta += math.Abs(next[id] - scores[id])
+		}
+		scores = next
+		if delta < eigenvectorTolerance {
+			break
+		}
+	}
+	degree := g.degree()
+	for _, id := range g.nodeIDs {
+		if degree[id] == 0 {
+			scores[id] = 0
+		}
+	}
+	return scores
+}
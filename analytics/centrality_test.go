@@ -0,0 +1,46 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"testing"
+)
+
+func TestRank(t *testing.T) {
+
+	// a star centered on hub, plus a heavy edge between two leaves
+	g := NewGraph(false)
+	for _, id := range []string{"a", "b", "c", "hub", "lonely"} {
+		g.AddNode(id, NodeUser, id)
+	}
+	g.AddEdge("hub", "a", EdgeCoThread, 1)
+	g.AddEdge("hub", "b", EdgeCoThread, 1)
+	g.AddEdge("hub", "c", EdgeCoThread, 1)
+	g.AddEdge("a", "b", EdgeCoThread, 5)
+	g.AddEdge("a", "b", EdgeReply, 1)
+
+	degree := g.Rank(Degree, 0)
+	if degree[0].Node.ID != "hub" || degree[0].Score != 3 {
+		t.Fatal("Should rank hub first by degree")
+	}
+	if degree[1].Node.ID != "a" || degree[1].Rank != 2 || degree[2].Rank != 2 || degree[1].Score != 2 {
+		t.Fatal("Should count parallel edges once, and share ranks")
+	}
+	if degree[4].Node.ID != "lonely" || degree[4].Score != 0 {
+		t.Fatal("Should rank isolated nodes last")
+	}
+
+	strength := g.Rank(Strength, 2)
+	if len(strength) != 2 || strength[0].Node.ID != "a" || strength[0].Score != 7 {
+		t.Fatal("Should rank by the weight of edges")
+	}
+
+	eigenvector := g.Rank(Eigenvector, 0)
+	if eigenvector[0].Score != 1 || eigenvector[0].Node.ID != "a" {
+		t.Fatal("Should normalize eigenvector centrality")
+	}
+	if eigenvector[2].Node.ID != "hub" || eigenvector[4].Score != 0 {
+		t.Fatal("Should score nodes by the centrality of their neighbours")
+	}
+}
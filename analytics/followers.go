@@ -0,0 +1,225 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// Kinds of the nodes and edges of a follower graph
+const (
+	NodeUser         = "user"
+	NodeForum        = "forum"
+	EdgeFollows      = "follows"
+	EdgeFollowsForum = "followsForum"
+)
+
+const (
+	userNodePrefix    = "user:"
+	forumNodePrefix   = "forum:"
+	defaultFollowRate = 3600 * time.Millisecond
+)
+
+// UserNodeID returns the id of the node of a user in graphs built by this package
+func UserNodeID(userID string) string {
+	return userNodePrefix + userID
+}
+
+// ForumNodeID returns the id of the node of a forum in graphs built by this package
+func ForumNodeID(forumID string) string {
+	return forumNodePrefix + forumID
+}
+
+/*
+FollowerCrawler walks the social graph breadth first, starting from a set of users, through UserFollowers,
+UserFollowing and UserForumFollowing. Users are expanded up to MaxDepth hops from the seeds, and the crawl stops adding
+users once the graph holds MaxNodes nodes (edges between users already in the graph are still recorded). At most
+MaxPerUser followers, and as many followed users and forums, are listed for every user.
+
+Requests are spaced by at least Interval, and are paused while Disqus reports the quota as exhausted.
+*/
+type FollowerCrawler struct {
+	gisqus     *gisqus.Gisqus
+	MaxDepth   int
+	MaxNodes   int
+	MaxPerUser int
+	Interval   time.Duration
+	Followers  bool
+	Following  bool
+	Forums     bool
+}
+
+/*
+NewFollowerCrawler returns a FollowerCrawler that follows followers, followed users and followed forums, 2 hops deep, up
+to 1000 nodes and 500 users per list, with one request every 3.6 seconds (the default quota of 1000 requests per hour).
+*/
+func NewFollowerCrawler(g *gisqus.Gisqus) *FollowerCrawler {
+	return &FollowerCrawler{
+		gisqus:     g,
+		MaxDepth:   2,
+		MaxNodes:   1000,
+		MaxPerUser: 500,
+		Interval:   defaultFollowRate,
+		Followers:  true,
+		Following:  true,
+		Forums:     true,
+	}
+}
+
+/*
+Crawl builds the directed follower graph reachable from seeds (user ids). An edge of kind EdgeFollows goes from a
+follower to the user it follows, an edge of kind EdgeFollowsForum from a user to a forum. User nodes carry the username
+as label, and the hops from the nearest seed as the "depth" attribute.
+
+If the crawl is interrupted by an error, the graph built so far is returned along with it.
+*/
+func (c *FollowerCrawler) Crawl(ctx context.Context, seeds ...string) (*Graph, error) {
+
+	graph := NewGraph(true)
+	depths := make(map[string]int)
+	var queue []string
+
+	addUser := func(user *gisqus.User, depth int) bool {
+		id := UserNodeID(user.ID)
+		if graph.Node(id) == nil {
+			if c.MaxNodes > 0 && len(graph.nodeIDs) >= c.MaxNodes {
+				return false
+			}
+			node := graph.AddNode(id, NodeUser, user.Username)
+			node.Attributes["depth"] = strconv.Itoa(depth)
+			depths[user.ID] = depth
+			queue = append(queue, user.ID)
+		} else {
+			graph.AddNode(id, NodeUser, user.Username)
+		}
+		return true
+	}
+	// the same follow can be listed twice, as a follower of one user and as followed by the other
+	follow := func(from, to, kind string) {
+		if graph.Edge(from, to, kind) == nil {
+			graph.AddEdge(from, to, kind, 1)
+		}
+	}
+	for _, seed := range seeds {
+		user := &gisqus.User{}
+		user.ID = seed
+		addUser(user, 0)
+	}
+
+	var last time.Time
+	throttle := func() error {
+		if wait := c.Interval - time.Since(last); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-ctx.Done():
+				timer.Stop()
+				return ctx.Err()
+			}
+		}
+		err := c.gisqus.WaitForQuota(ctx)
+		last = time.Now()
+		return err
+	}
+
+	for len(queue) > 0 {
+		userID := queue[0]
+		queue = queue[1:]
+		depth := depths[userID]
+		if depth >= c.MaxDepth {
+			continue
+		}
+
+		if c.Followers {
+			followers, err := listPages(c, throttle, func(values url.Values) ([]*gisqus.User, *gisqus.DisqusCursor, error) {
+				resp, err := c.gisqus.UserFollowers(ctx, userID, values)
+				if err != nil {
+					return nil, nil, err
+				}
+				return resp.Response, resp.Cursor, nil
+			})
+			if err != nil {
+				return graph, err
+			}
+			for _, follower := range followers {
+				if addUser(follower, depth+1) {
+					follow(UserNodeID(follower.ID), UserNodeID(userID), EdgeFollows)
+				}
+			}
+		}
+
+		if c.Following {
+			following, err := listPages(c, throttle, func(values url.Values) ([]*gisqus.User, *gisqus.DisqusCursor, error) {
+				resp, err := c.gisqus.UserFollowing(ctx, userID, values)
+				if err != nil {
+					return nil, nil, err
+				}
+				return resp.Response, resp.Cursor, nil
+			})
+			if err != nil {
+				return graph, err
+			}
+			for _, followed := range following {
+				if addUser(followed, depth+1) {
+					follow(UserNodeID(userID), UserNodeID(followed.ID), EdgeFollows)
+				}
+			}
+		}
+
+		if c.Forums {
+			forums, err := listPages(c, throttle, func(values url.Values) ([]*gisqus.Forum, *gisqus.DisqusCursor, error) {
+				resp, err := c.gisqus.UserForumFollowing(ctx, userID, values)
+				if err != nil {
+					return nil, nil, err
+				}
+				return resp.Response, resp.Cursor, nil
+			})
+			if err != nil {
+				return graph, err
+			}
+			for _, forum := range forums {
+				id := ForumNodeID(forum.ID)
+				if graph.Node(id) == nil && c.MaxNodes > 0 && len(graph.nodeIDs) >= c.MaxNodes {
+					continue
+				}
+				graph.AddNode(id, NodeForum, forum.Name)
+				follow(UserNodeID(userID), id, EdgeFollowsForum)
+			}
+		}
+	}
+	return graph, nil
+}
+
+// listPages fetches pages until the list is exhausted or MaxPerUser items have been listed
+func listPages[T any](c *FollowerCrawler, throttle func() error, page func(url.Values) ([]T, *gisqus.DisqusCursor, error)) ([]T, error) {
+
+	var items []T
+	values := url.Values{}
+	values.Set("limit", "100")
+	for {
+		err := throttle()
+		if err != nil {
+			return nil, err
+		}
+		batch, cursor, err := page(values)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, batch...)
+		if c.MaxPerUser > 0 && len(items) >= c.MaxPerUser {
+			return items[:c.MaxPerUser], nil
+		}
+		if cursor == nil || !cursor.HasNext || cursor.Next == "" {
+			return items, nil
+		}
+		values = url.Values{}
+		values.Set("limit", "100")
+		values.Set("cursor", cursor.Next)
+	}
+}
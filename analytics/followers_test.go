@@ -0,0 +1,108 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pierods/gisqus"
+)
+
+func TestFollowerCrawler(t *testing.T) {
+
+	// 1 <- 2 <- 3 <- 4: every user is followed by the next one
+	followers := map[string]string{
+		"1": `{"code":0,"cursor":{"hasNext":true,"next":"p2"},"response":[{"id":"2","username":"bob"}]}`,
+		"2": `{"code":0,"response":[{"id":"3","username":"carol"}]}`,
+		"3": `{"code":0,"response":[{"id":"4","username":"dave"}]}`,
+	}
+	calls := 0
+	g := gisqus.NewGisqus("secret")
+	g.Use(func(next gisqus.Doer) gisqus.Doer {
+		return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+			calls++
+			body := `{"code":0,"response":[]}`
+			switch req.Endpoint {
+			case "users/listFollowers":
+				if followers[req.Values.Get("user")] != "" {
+					body = followers[req.Values.Get("user")]
+				}
+				if req.Values.Get("cursor") == "p2" {
+					body = `{"code":0,"response":[{"id":"5","username":"eve"}]}`
+				}
+			case "users/listFollowingForums":
+				if req.Values.Get("user") == "1" {
+					body = `{"code":0,"response":[{"id":"tmz","name":"TMZ"}]}`
+				}
+			}
+			return &gisqus.Response{StatusCode: 200, Body: []byte(body)}, nil
+		})
+	})
+
+	c := NewFollowerCrawler(&g)
+	c.Interval = 0
+	graph, err := c.Crawl(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if graph.Node(UserNodeID("3")) == nil || graph.Node(UserNodeID("4")) != nil {
+		t.Fatal("Should stop at MaxDepth")
+	}
+	if graph.Node(UserNodeID("5")) == nil {
+		t.Fatal("Should follow cursors")
+	}
+	if graph.Node(ForumNodeID("tmz")).Label != "TMZ" {
+		t.Fatal("Should add followed forums")
+	}
+	if graph.Node(UserNodeID("3")).Attributes["depth"] != "2" {
+		t.Fatal("Should record the depth of users")
+	}
+	edges := graph.Edges()
+	if edges[0].From != UserNodeID("2") || edges[0].To != UserNodeID("1") || edges[0].Kind != EdgeFollows {
+		t.Fatal("Should link followers to the users they follow")
+	}
+	// user 1: 2 follower pages, following, forums; users 2 and 5: followers, following, forums
+	if calls != 10 {
+		t.Fatal("Should not expand users beyond MaxDepth")
+	}
+
+	c.MaxNodes = 2
+	graph, err = c.Crawl(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(graph.Nodes()) != 2 {
+		t.Fatal("Should stop at MaxNodes")
+	}
+}
+
+func TestFollowerCrawlerMutual(t *testing.T) {
+
+	// 2 follows 1, and is listed both as a follower of 1 and as following 1
+	g := gisqus.NewGisqus("secret")
+	g.Use(func(next gisqus.Doer) gisqus.Doer {
+		return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+			body := `{"code":0,"response":[]}`
+			switch {
+			case req.Endpoint == "users/listFollowers" && req.Values.Get("user") == "1":
+				body = `{"code":0,"response":[{"id":"2","username":"bob"}]}`
+			case req.Endpoint == "users/listFollowing" && req.Values.Get("user") == "2":
+				body = `{"code":0,"response":[{"id":"1","username":"alice"}]}`
+			}
+			return &gisqus.Response{StatusCode: 200, Body: []byte(body)}, nil
+		})
+	})
+
+	c := NewFollowerCrawler(&g)
+	c.Interval = 0
+	graph, err := c.Crawl(context.Background(), "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges := graph.Edges()
+	if len(edges) != 1 || edges[0].Weight != 1 {
+		t.Fatal("Should record a follow once when both ends are expanded")
+	}
+}
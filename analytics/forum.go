@@ -0,0 +1,200 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// Bucket represents the width of the buckets of a time series
+type Bucket string
+
+// Bucket constants. Buckets are aligned to UTC, weeks start on Monday.
+const (
+	Hourly Bucket = "hour"
+	Daily  Bucket = "day"
+	Weekly Bucket = "week"
+)
+
+func (b Bucket) start(t time.Time) time.Time {
+
+	t = t.UTC()
+	switch b {
+	case Hourly:
+		return t.Truncate(time.Hour)
+	case Weekly:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// width returns the duration of a bucket. Buckets are aligned to UTC, which has no daylight saving: widths are fixed.
+func (b Bucket) width() time.Duration {
+
+	switch b {
+	case Hourly:
+		return time.Hour
+	case Weekly:
+		return 7 * 24 * time.Hour
+	default:
+		return 24 * time.Hour
+	}
+}
+
+// ActivityPoint models the activity of a forum in a bucket
+type ActivityPoint struct {
+	Start         time.Time `json:"start"`
+	Threads       int       `json:"threads"`
+	Posts         int       `json:"posts"`
+	Likes         int       `json:"likes"`
+	UniqueAuthors int       `json:"uniqueAuthors"`
+	Flagged       int       `json:"flagged"`
+	Spam          int       `json:"spam"`
+
+	authors map[string]bool
+}
+
+// ActivitySeries models the activity of a forum over time. It has a point per bucket, including empty ones.
+type ActivitySeries struct {
+	Forum  string           `json:"forum"`
+	Bucket Bucket           `json:"bucket"`
+	Points []*ActivityPoint `json:"points"`
+}
+
+/*
+ForumActivity walks the threads (with ForumThreads) and posts (with PostList) created in forum between from (included)
+and to (excluded), and aggregates them in buckets. Posts are fetched including spam and flagged ones, so that they can be
+counted.
+*/
+func ForumActivity(ctx context.Context, g *gisqus.Gisqus, forum string, from, to time.Time, bucket Bucket) (*ActivitySeries, error) {
+
+	series := NewActivitySeries(forum, from, to, bucket)
+
+	values := url.Values{}
+	values.Set("since", gisqus.ToDisqusTime(from.UTC()))
+	values.Set("order", string(gisqus.OrderAsc))
+	values.Set("limit", "100")
+	for thread, err := range g.ForumThreadsAll(ctx, forum, values) {
+		if err != nil {
+			return nil, err
+		}
+		if !thread.CreatedAt.Before(to) {
+			break
+		}
+		series.AddThread(thread)
+	}
+
+	values = url.Values{}
+	values.Set("forum", forum)
+	for _, include := range []gisqus.Include{gisqus.PostIsApproved, gisqus.PostIsUnapproved, gisqus.PostIsSpam, gisqus.PostIncludedIsFlagged} {
+		values.Add("include", string(include))
+	}
+	for post, err := range gisqus.NewTimeWindowCrawler(g).Posts(ctx, from, to, values) {
+		if err != nil {
+			return nil, err
+		}
+		series.AddPost(post)
+	}
+	return series, nil
+}
+
+// NewActivitySeries returns an empty series of buckets covering from (included) to to (excluded)
+func NewActivitySeries(forum string, from, to time.Time, bucket Bucket) *ActivitySeries {
+
+	series := &ActivitySeries{
+		Forum:  forum,
+		Bucket: bucket,
+	}
+	for start := bucket.start(from); start.Before(to); start = start.Add(bucket.width()) {
+		series.Points = append(series.Points, &ActivityPoint{
+			Start:   start,
+			authors: make(map[string]bool),
+		})
+	}
+	return series
+}
+
+func (s *ActivitySeries) point(t time.Time) *ActivityPoint {
+
+	if len(s.Points) == 0 {
+		return nil
+	}
+	offset := s.Bucket.start(t).Sub(s.Points[0].Start)
+	if offset < 0 {
+		return nil
+	}
+	i := int(offset / s.Bucket.width())
+	if i >= len(s.Points) {
+		return nil
+	}
+	return s.Points[i]
+}
+
+// AddThread counts thread in its bucket. Threads out of the range of the series are ignored.
+func (s *ActivitySeries) AddThread(thread *gisqus.Thread) {
+
+	if p := s.point(thread.CreatedAt); p != nil {
+		p.Threads++
+	}
+}
+
+// AddPost counts post in its bucket. Posts out of the range of the series are ignored.
+func (s *ActivitySeries) AddPost(post *gisqus.Post) {
+
+	p := s.point(post.CreatedAt)
+	if p == nil {
+		return
+	}
+	p.Posts++
+	p.Likes += post.Likes
+	if post.IsFlagged {
+		p.Flagged++
+	}
+	if post.IsSpam {
+		p.Spam++
+	}
+	if key, _ := authorKey(post.Author); key != "" && !p.authors[key] {
+		p.authors[key] = true
+		p.UniqueAuthors++
+	}
+}
+
+// WriteCSV writes the series as CSV, with a header line
+func (s *ActivitySeries) WriteCSV(w io.Writer) error {
+
+	if s == nil {
+		return errors.New("nil series")
+	}
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"start", "threads", "posts", "likes", "unique_authors", "flagged", "spam"})
+	if err != nil {
+		return err
+	}
+	for _, p := range s.Points {
+		err = cw.Write([]string{
+			p.Start.Format(time.RFC3339),
+			strconv.Itoa(p.Threads),
+			strconv.Itoa(p.Posts),
+			strconv.Itoa(p.Likes),
+			strconv.Itoa(p.UniqueAuthors),
+			strconv.Itoa(p.Flagged),
+			strconv.Itoa(p.Spam),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
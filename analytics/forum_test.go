@@ -0,0 +1,103 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// fakeDisqus answers calls with canned bodies, by endpoint
+func fakeDisqus(bodies map[string]string) *gisqus.Gisqus {
+
+	g := gisqus.NewGisqus("secret")
+	g.Use(func(next gisqus.Doer) gisqus.Doer {
+		return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+			return &gisqus.Response{StatusCode: 200, Body: []byte(bodies[req.Endpoint])}, nil
+		})
+	})
+	return &g
+}
+
+func TestForumActivity(t *testing.T) {
+
+	g := fakeDisqus(map[string]string{
+		"forums/listThreads": `{"code":0,"cursor":{"hasNext":false},"response":[
+			{"id":"1","createdAt":"2017-05-23T10:10:00"},
+			{"id":"2","createdAt":"2017-05-24T10:10:00"},
+			{"id":"3","createdAt":"2017-05-26T10:10:00"}]}`,
+		"posts/list": `{"code":0,"cursor":{"hasNext":false},"response":[
+			{"id":"10","createdAt":"2017-05-23T10:20:00","likes":2,"author":{"id":"1"}},
+			{"id":"11","createdAt":"2017-05-23T11:20:00","likes":1,"author":{"id":"1"}},
+			{"id":"12","createdAt":"2017-05-23T12:20:00","isSpam":true,"author":{"id":"2"}},
+			{"id":"13","createdAt":"2017-05-24T12:20:00","isFlagged":true,"author":{"id":"2"}}]}`,
+	})
+
+	from := time.Date(2017, 5, 23, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+	series, err := ForumActivity(context.Background(), g, "tmz", from, to, Daily)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series.Points) != 3 {
+		t.Fatal("Should have a point per bucket")
+	}
+	day1, day2, day3 := series.Points[0], series.Points[1], series.Points[2]
+	if day1.Threads != 1 || day2.Threads != 1 || day3.Threads != 0 {
+		t.Fatal("Should count threads in range")
+	}
+	if day1.Posts != 3 || day1.Likes != 3 || day1.UniqueAuthors != 2 || day1.Spam != 1 {
+		t.Fatal("Should aggregate posts", day1)
+	}
+	if day2.Posts != 1 || day2.Flagged != 1 {
+		t.Fatal("Should count flagged posts", day2)
+	}
+
+	var csvOut bytes.Buffer
+	err = series.WriteCSV(&csvOut)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	if len(lines) != 4 || lines[1] != "2017-05-23T00:00:00Z,1,3,3,2,0,1" {
+		t.Fatal("Should write series as CSV", lines)
+	}
+	jsonOut, err := json.Marshal(series)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(jsonOut), `"bucket":"day"`) || !strings.Contains(string(jsonOut), `"uniqueAuthors":2`) {
+		t.Fatal("Should serialize series to JSON")
+	}
+}
+
+func TestBuckets(t *testing.T) {
+
+	// a wednesday
+	ts := time.Date(2017, 5, 24, 13, 45, 0, 0, time.UTC)
+	if !Hourly.start(ts).Equal(time.Date(2017, 5, 24, 13, 0, 0, 0, time.UTC)) {
+		t.Fatal("Should align hourly buckets")
+	}
+	if !Weekly.start(ts).Equal(time.Date(2017, 5, 22, 0, 0, 0, 0, time.UTC)) {
+		t.Fatal("Should start weekly buckets on monday")
+	}
+	series := NewActivitySeries("tmz", ts, ts.Add(3*time.Hour), Hourly)
+	if len(series.Points) != 4 {
+		t.Fatal("Should cover the range with buckets")
+	}
+
+	series = NewActivitySeries("tmz", ts, ts.AddDate(0, 0, 20), Weekly)
+	if series.point(ts.AddDate(0, 0, 13)) != series.Points[2] {
+		t.Fatal("Should find the bucket of a date")
+	}
+	if series.point(ts.AddDate(0, 0, -3)) != nil || series.point(ts.AddDate(0, 0, 30)) != nil {
+		t.Fatal("Should ignore dates out of the series")
+	}
+}
@@ -0,0 +1,417 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Node models a vertex of a Graph. Attributes are exported along with the node.
+type Node struct {
+	ID         string
+	Kind       string
+	Label      string
+	Attributes map[string]string
+}
+
+// Edge models a link of a Graph. Adding the same edge more than once sums up its weight.
+type Edge struct {
+	From   string
+	To     string
+	Kind   string
+	Weight float64
+}
+
+// Graph is an in-memory graph of users and forums, which can be exported to GraphML, GEXF and DOT
+type Graph struct {
+	Directed bool
+	nodes    map[string]*Node
+	nodeIDs  []string
+	edges    map[edgeKey]*Edge
+	edgeKeys []edgeKey
+}
+
+type edgeKey struct {
+	from, to, kind string
+}
+
+// NewGraph returns an empty graph
+func NewGraph(directed bool) *Graph {
+	return &Graph{
+		Directed: directed,
+		nodes:    make(map[string]*Node),
+		edges:    make(map[edgeKey]*Edge),
+	}
+}
+
+/*
+AddNode adds a node to the graph and returns it. If a node with the same id exists, it is returned instead, and its
+label is filled in if it was empty.
+*/
+func (g *Graph) AddNode(id, kind, label string) *Node {
+
+	if node, ok := g.nodes[id]; ok {
+		if node.Label == "" {
+			node.Label = label
+		}
+		return node
+	}
+	node := &Node{
+		ID:         id,
+		Kind:       kind,
+		Label:      label,
+		Attributes: make(map[string]string),
+	}
+	g.nodes[id] = node
+	g.nodeIDs = append(g.nodeIDs, id)
+	return node
+}
+
+/*
+AddEdge adds weight to the edge of kind between from and to, creating it if needed. In undirected graphs, from and to
+can be swapped. Both nodes must have been added beforehand.
+*/
+func (g *Graph) AddEdge(from, to, kind string, weight float64) *Edge {
+
+	if !g.Directed && to < from {
+		from, to = to, from
+	}
+	key := edgeKey{from, to, kind}
+	if edge, ok := g.edges[key]; ok {
+		edge.Weight += weight
+		return edge
+	}
+	edge := &Edge{
+		From:   from,
+		To:     to,
+		Kind:   kind,
+		Weight: weight,
+	}
+	g.edges[key] = edge
+	g.edgeKeys = append(g.edgeKeys, key)
+	return edge
+}
+
+// Node returns the node with the given id, or nil
+func (g *Graph) Node(id string) *Node {
+	return g.nodes[id]
+}
+
+// Edge returns the edge of kind between from and to, or nil. In undirected graphs, from and to can be swapped.
+func (g *Graph) Edge(from, to, kind string) *Edge {
+
+	if !g.Directed && to < from {
+		from, to = to, from
+	}
+	return g.edges[edgeKey{from, to, kind}]
+}
+
+// Nodes returns the nodes of the graph, in the order they were added
+func (g *Graph) Nodes() []*Node {
+
+	nodes := make([]*Node, len(g.nodeIDs))
+	for i, id := range g.nodeIDs {
+		nodes[i] = g.nodes[id]
+	}
+	return nodes
+}
+
+// Edges returns the edges of the graph, in the order they were added
+func (g *Graph) Edges() []*Edge {
+
+	edges := make([]*Edge, len(g.edgeKeys))
+	for i, key := range g.edgeKeys {
+		edges[i] = g.edges[key]
+	}
+	return edges
+}
+
+/*
+TopEdges returns the heaviest edges of kind (of all kinds if kind is empty), and keeps the first size (all if size <= 0).
+In a co-commenting network, pairs of users replying to each other far more than to anybody else are worth a look as
+potential sockpuppets.
+*/
+func (g *Graph) TopEdges(kind string, size int) []*Edge {
+
+	var edges []*Edge
+	for _, edge := range g.Edges() {
+		if kind == "" || edge.Kind == kind {
+			edges = append(edges, edge)
+		}
+	}
+	sort.SliceStable(edges, func(i, j int) bool { return edges[i].Weight > edges[j].Weight })
+	if size > 0 && len(edges) > size {
+		edges = edges[:size]
+	}
+	return edges
+}
+
+func (g *Graph) attributeNames() []string {
+
+	seen := make(map[string]bool)
+	var names []string
+	for _, node := range g.nodes {
+		for name := range node.Attributes {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (g *Graph) edgeType() string {
+	if g.Directed {
+		return "directed"
+	}
+	return "undirected"
+}
+
+func formatWeight(w float64) string {
+	return strconv.FormatFloat(w, 'f', -1, 64)
+}
+
+// WriteEdgeList writes the edges of the graph as CSV, with a header line: source, target, kind, weight
+func (g *Graph) WriteEdgeList(w io.Writer) error {
+
+	cw := csv.NewWriter(w)
+	err := cw.Write([]string{"source", "target", "kind", "weight"})
+	if err != nil {
+		return err
+	}
+	for _, edge := range g.Edges() {
+		err = cw.Write([]string{edge.From, edge.To, edge.Kind, formatWeight(edge.Weight)})
+		if err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+type xmlData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLKey struct {
+	ID   string `xml:"id,attr"`
+	For  string `xml:"for,attr"`
+	Name string `xml:"attr.name,attr"`
+	Type string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string    `xml:"id,attr"`
+	Data []xmlData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	Source string    `xml:"source,attr"`
+	Target string    `xml:"target,attr"`
+	Data   []xmlData `xml:"data"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+/*
+WriteGraphML writes the graph in the GraphML format (http://graphml.graphdrawing.org/). Kind, label and attributes of
+nodes, and kind and weight of edges, are exported as data.
+*/
+func (g *Graph) WriteGraphML(w io.Writer) error {
+
+	doc := graphML{XMLNS: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = []graphMLKey{
+		{ID: "kind", For: "node", Name: "kind", Type: "string"},
+		{ID: "label", For: "node", Name: "label", Type: "string"},
+	}
+	names := g.attributeNames()
+	for i, name := range names {
+		doc.Keys = append(doc.Keys, graphMLKey{ID: "a" + strconv.Itoa(i), For: "node", Name: name, Type: "string"})
+	}
+	doc.Keys = append(doc.Keys,
+		graphMLKey{ID: "edgekind", For: "edge", Name: "kind", Type: "string"},
+		graphMLKey{ID: "weight", For: "edge", Name: "weight", Type: "double"},
+	)
+
+	doc.Graph.ID = "G"
+	doc.Graph.EdgeDefault = g.edgeType()
+	for _, node := range g.Nodes() {
+		n := graphMLNode{
+			ID:   node.ID,
+			Data: []xmlData{{"kind", node.Kind}, {"label", node.Label}},
+		}
+		for i, name := range names {
+			if value, ok := node.Attributes[name]; ok {
+				n.Data = append(n.Data, xmlData{"a" + strconv.Itoa(i), value})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for _, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			Source: edge.From,
+			Target: edge.To,
+			Data:   []xmlData{{"edgekind", edge.Kind}, {"weight", formatWeight(edge.Weight)}},
+		})
+	}
+	return writeXML(w, doc)
+}
+
+type gexfAttribute struct {
+	ID    string `xml:"id,attr"`
+	Title string `xml:"title,attr"`
+	Type  string `xml:"type,attr"`
+}
+
+type gexfAttributes struct {
+	Class      string          `xml:"class,attr"`
+	Attributes []gexfAttribute `xml:"attribute"`
+}
+
+type gexfValue struct {
+	For   string `xml:"for,attr"`
+	Value string `xml:"value,attr"`
+}
+
+type gexfNode struct {
+	ID     string      `xml:"id,attr"`
+	Label  string      `xml:"label,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexfEdge struct {
+	ID     string      `xml:"id,attr"`
+	Source string      `xml:"source,attr"`
+	Target string      `xml:"target,attr"`
+	Weight string      `xml:"weight,attr"`
+	Values []gexfValue `xml:"attvalues>attvalue"`
+}
+
+type gexf struct {
+	XMLName xml.Name `xml:"gexf"`
+	XMLNS   string   `xml:"xmlns,attr"`
+	Version string   `xml:"version,attr"`
+	Graph   struct {
+		DefaultEdgeType string           `xml:"defaultedgetype,attr"`
+		Mode            string           `xml:"mode,attr"`
+		Attributes      []gexfAttributes `xml:"attributes"`
+		Nodes           []gexfNode       `xml:"nodes>node"`
+		Edges           []gexfEdge       `xml:"edges>edge"`
+	} `xml:"graph"`
+}
+
+/*
+WriteGEXF writes the graph in the GEXF 1.3 format (https://gexf.net/), as used by Gephi. Kind and attributes of nodes,
+and kind of edges, are exported as attributes.
+*/
+func (g *Graph) WriteGEXF(w io.Writer) error {
+
+	doc := gexf{XMLNS: "http://gexf.net/1.3", Version: "1.3"}
+	doc.Graph.DefaultEdgeType = g.edgeType()
+	doc.Graph.Mode = "static"
+
+	names := append([]string{"kind"}, g.attributeNames()...)
+	nodeAttributes := gexfAttributes{Class: "node"}
+	for i, name := range names {
+		nodeAttributes.Attributes = append(nodeAttributes.Attributes, gexfAttribute{strconv.Itoa(i), name, "string"})
+	}
+	edgeAttributes := gexfAttributes{Class: "edge", Attributes: []gexfAttribute{{"0", "kind", "string"}}}
+	doc.Graph.Attributes = []gexfAttributes{nodeAttributes, edgeAttributes}
+
+	for _, node := range g.Nodes() {
+		n := gexfNode{
+			ID:     node.ID,
+			Label:  node.Label,
+			Values: []gexfValue{{"0", node.Kind}},
+		}
+		for i, name := range names[1:] {
+			if value, ok := node.Attributes[name]; ok {
+				n.Values = append(n.Values, gexfValue{strconv.Itoa(i + 1), value})
+			}
+		}
+		doc.Graph.Nodes = append(doc.Graph.Nodes, n)
+	}
+	for i, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, gexfEdge{
+			ID:     strconv.Itoa(i),
+			Source: edge.From,
+			Target: edge.To,
+			Weight: formatWeight(edge.Weight),
+			Values: []gexfValue{{"0", edge.Kind}},
+		})
+	}
+	return writeXML(w, doc)
+}
+
+func writeXML(w io.Writer, doc interface{}) error {
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	err = enc.Encode(doc)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+/*
+WriteDOT writes the graph in the DOT format (https://graphviz.org/doc/info/lang.html). Kind and attributes of nodes,
+and kind and weight of edges, are exported as attributes.
+*/
+func (g *Graph) WriteDOT(w io.Writer) error {
+
+	bw := bufio.NewWriter(w)
+	graph, link := "graph", "--"
+	if g.Directed {
+		graph, link = "digraph", "->"
+	}
+	fmt.Fprintf(bw, "%s G {\n", graph)
+
+	names := g.attributeNames()
+	for _, node := range g.Nodes() {
+		fmt.Fprintf(bw, "  %s [label=%s, kind=%s", dotQuote(node.ID), dotQuote(node.Label), dotQuote(node.Kind))
+		for _, name := range names {
+			if value, ok := node.Attributes[name]; ok {
+				fmt.Fprintf(bw, ", %s=%s", dotQuote(name), dotQuote(value))
+			}
+		}
+		fmt.Fprint(bw, "];\n")
+	}
+	for _, edge := range g.Edges() {
+		fmt.Fprintf(bw, "  %s %s %s [kind=%s, weight=%s];\n", dotQuote(edge.From), link, dotQuote(edge.To), dotQuote(edge.Kind), formatWeight(edge.Weight))
+	}
+	fmt.Fprint(bw, "}\n")
+	return bw.Flush()
+}
+
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func dotQuote(s string) string {
+	return `"` + dotEscaper.Replace(s) + `"`
+}
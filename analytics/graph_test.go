@@ -0,0 +1,118 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"bytes"
+	"encoding/xml"
+	"strings"
+	"testing"
+)
+
+func testGraph() *Graph {
+
+	g := NewGraph(true)
+	g.AddNode("user:1", NodeUser, "alice").Attributes["depth"] = "0"
+	g.AddNode("user:2", NodeUser, `bob "the builder"`)
+	g.AddNode("forum:tmz", NodeForum, "TMZ")
+	g.AddEdge("user:2", "user:1", EdgeFollows, 1)
+	g.AddEdge("user:1", "forum:tmz", EdgeFollowsForum, 1)
+	return g
+}
+
+func TestGraph(t *testing.T) {
+
+	g := testGraph()
+	if g.AddNode("user:1", NodeUser, "other").Label != "alice" || len(g.Nodes()) != 3 {
+		t.Fatal("Should not add a node twice")
+	}
+	g.AddEdge("user:2", "user:1", EdgeFollows, 2)
+	if len(g.Edges()) != 2 || g.Edges()[0].Weight != 3 {
+		t.Fatal("Should sum up the weight of repeated edges")
+	}
+
+	u := NewGraph(false)
+	u.AddEdge("b", "a", "x", 1)
+	u.AddEdge("a", "b", "x", 1)
+	if len(u.Edges()) != 1 || u.Edges()[0].Weight != 2 {
+		t.Fatal("Should not distinguish the direction of edges in undirected graphs")
+	}
+
+	u.AddEdge("a", "c", "x", 5)
+	u.AddEdge("a", "c", "y", 7)
+	top := u.TopEdges("x", 1)
+	if len(top) != 1 || top[0].To != "c" || top[0].Weight != 5 {
+		t.Fatal("Should return the heaviest edges of a kind")
+	}
+	if len(u.TopEdges("", 0)) != 3 || u.TopEdges("", 0)[0].Kind != "y" {
+		t.Fatal("Should return the heaviest edges of all kinds")
+	}
+}
+
+func TestGraphExports(t *testing.T) {
+
+	g := testGraph()
+
+	var buf bytes.Buffer
+	err := g.WriteGraphML(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var graphml graphML
+	if xml.Unmarshal(buf.Bytes(), &graphml) != nil {
+		t.Fatal("Should write valid GraphML")
+	}
+	if len(graphml.Graph.Nodes) != 3 || len(graphml.Graph.Edges) != 2 || graphml.Graph.EdgeDefault != "directed" {
+		t.Fatal("Should write all nodes and edges to GraphML")
+	}
+	if graphml.Graph.Edges[0].Source != "user:2" || graphml.Graph.Nodes[0].Data[2].Value != "0" {
+		t.Fatal("Should write edges and attributes to GraphML")
+	}
+
+	buf.Reset()
+	err = g.WriteGEXF(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gx gexf
+	if xml.Unmarshal(buf.Bytes(), &gx) != nil {
+		t.Fatal("Should write valid GEXF")
+	}
+	if len(gx.Graph.Nodes) != 3 || len(gx.Graph.Edges) != 2 || gx.Graph.Nodes[1].Label != `bob "the builder"` {
+		t.Fatal("Should write all nodes and edges to GEXF")
+	}
+
+	buf.Reset()
+	err = g.WriteDOT(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dot := buf.String()
+	if !strings.HasPrefix(dot, "digraph G {") || !strings.Contains(dot, `"user:2" -> "user:1" [kind="follows", weight=1];`) {
+		t.Fatal("Should write edges to DOT")
+	}
+	if !strings.Contains(dot, `label="bob \"the builder\""`) {
+		t.Fatal("Should escape quotes in DOT")
+	}
+}
+
+func TestWriteEdgeList(t *testing.T) {
+
+	var buf bytes.Buffer
+	err := testGraph().WriteEdgeList(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "source,target,kind,weight\nuser:2,user:1,follows,1\nuser:1,forum:tmz,followsForum,1\n"
+	if buf.String() != expected {
+		t.Fatal("Should write one line per edge")
+	}
+}
+
+func TestFormatWeight(t *testing.T) {
+
+	if formatWeight(1000000) != "1000000" || formatWeight(2) != "2" || formatWeight(1.5) != "1.5" {
+		t.Fatal("Should write integer weights as integers in every export")
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// Metric represents what users are ranked by in a leaderboard
+type Metric string
+
+// Metric constants. They refer to the Disqus wide statistics of users.
+const (
+	ByPosts      Metric = "posts"
+	ByLikes      Metric = "likes"
+	ByReputation Metric = "reputation"
+	ByFollowers  Metric = "followers"
+)
+
+func (m Metric) score(user *gisqus.User) float64 {
+
+	switch m {
+	case ByLikes:
+		return float64(user.NumLikesReceived)
+	case ByReputation:
+		return float64(user.Rep)
+	case ByFollowers:
+		return float64(user.NumFollowers)
+	default:
+		return float64(user.NumPosts)
+	}
+}
+
+// LeaderboardEntry models the position of a user in a leaderboard
+type LeaderboardEntry struct {
+	Rank  int          `json:"rank"`
+	Score float64      `json:"score"`
+	User  *gisqus.User `json:"user"`
+}
+
+// Leaderboard models a ranking of users. It can be saved as JSON, to be compared later with DiffLeaderboards.
+type Leaderboard struct {
+	Metric  Metric              `json:"metric"`
+	Forums  []string            `json:"forums"`
+	TakenAt time.Time           `json:"takenAt"`
+	Entries []*LeaderboardEntry `json:"entries"`
+	// Missing lists the candidates left out because their details could not be fetched
+	Missing []string `json:"missing,omitempty"`
+}
+
+/*
+BuildLeaderboard ranks the users of forums by metric, and keeps the first size (all if size <= 0). Candidates are the
+most active and the most liked users of every forum (ForumMostActiveUsers and ForumMostLikedUsers), whose statistics are
+then fetched with UserDetailsMany. Users whose details cannot be fetched are left out and listed in Missing; if no
+details can be fetched at all, an error is returned.
+*/
+func BuildLeaderboard(ctx context.Context, g *gisqus.Gisqus, forums []string, metric Metric, size int) (*Leaderboard, error) {
+
+	var ids []string
+	seen := make(map[string]bool)
+	add := func(users []*gisqus.User) {
+		for _, user := range users {
+			if !seen[user.ID] && !user.IsAnonymous {
+				seen[user.ID] = true
+				ids = append(ids, user.ID)
+			}
+		}
+	}
+	for _, forum := range forums {
+		values := url.Values{}
+		values.Set("limit", "100")
+		active, err := g.ForumMostActiveUsers(ctx, forum, values)
+		if err != nil {
+			return nil, err
+		}
+		add(active.Response)
+		values = url.Values{}
+		values.Set("limit", "100")
+		liked, err := g.ForumMostLikedUsers(ctx, forum, values)
+		if err != nil {
+			return nil, err
+		}
+		add(liked.Response)
+	}
+
+	details, errs := g.UserDetailsMany(ctx, ids, url.Values{}, 4)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 && len(details) == 0 {
+		return nil, fmt.Errorf("fetching the details of %d users: %w", len(ids), errs[ids[0]])
+	}
+	users := make([]*gisqus.User, 0, len(details))
+	var missing []string
+	for _, id := range ids {
+		if user, ok := details[id]; ok {
+			users = append(users, user)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	lb := RankUsers(users, metric, size)
+	lb.Forums = forums
+	lb.Missing = missing
+	return lb, nil
+}
+
+/*
+RankUsers ranks users by metric, and keeps the first size (all if size <= 0). Users with the same score share the same
+rank (1, 2, 2, 4...).
+*/
+func RankUsers(users []*gisqus.User, metric Metric, size int) *Leaderboard {
+
+	sorted := append([]*gisqus.User(nil), users...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		si, sj := metric.score(sorted[i]), metric.score(sorted[j])
+		if si != sj {
+			return si > sj
+		}
+		return sorted[i].Username < sorted[j].Username
+	})
+	if size > 0 && len(sorted) > size {
+		sorted = sorted[:size]
+	}
+
+	lb := &Leaderboard{
+		Metric:  metric,
+		TakenAt: time.Now(),
+	}
+	for i, user := range sorted {
+		entry := &LeaderboardEntry{
+			Rank:  i + 1,
+			Score: metric.score(user),
+			User:  user,
+		}
+		if i > 0 && entry.Score == lb.Entries[i-1].Score {
+			entry.Rank = lb.Entries[i-1].Rank
+		}
+		lb.Entries = append(lb.Entries, entry)
+	}
+	return lb
+}
+
+// Movement models the change of position of a user between two leaderboards
+type Movement struct {
+	UserID   string `json:"userId"`
+	Username string `json:"username"`
+	// OldRank and NewRank are 0 when the user is absent from the leaderboard
+	OldRank int `json:"oldRank"`
+	NewRank int `json:"newRank"`
+	// Change is positive for users moving up, negative for users moving down
+	Change     int     `json:"change"`
+	ScoreDelta float64 `json:"scoreDelta"`
+	Entered    bool    `json:"entered"`
+	Dropped    bool    `json:"dropped"`
+}
+
+/*
+DiffLeaderboards compares two leaderboards and returns the movements of users: users of after ordered by rank, then users
+that dropped out of it ordered by their old rank.
+*/
+func DiffLeaderboards(before, after *Leaderboard) []*Movement {
+
+	old := make(map[string]*LeaderboardEntry, len(before.Entries))
+	for _, entry := range before.Entries {
+		old[entry.User.ID] = entry
+	}
+
+	var movements []*Movement
+	current := make(map[string]bool, len(after.Entries))
+	for _, entry := range after.Entries {
+		current[entry.User.ID] = true
+		m := &Movement{
+			UserID:     entry.User.ID,
+			Username:   entry.User.Username,
+			NewRank:    entry.Rank,
+			ScoreDelta: entry.Score,
+		}
+		if prev, ok := old[entry.User.ID]; ok {
+			m.OldRank = prev.Rank
+			m.Change = prev.Rank - entry.Rank
+			m.ScoreDelta = entry.Score - prev.Score
+		} else {
+			m.Entered = true
+		}
+		movements = append(movements, m)
+	}
+	for _, entry := range before.Entries {
+		if !current[entry.User.ID] {
+			movements = append(movements, &Movement{
+				UserID:     entry.User.ID,
+				Username:   entry.User.Username,
+				OldRank:    entry.Rank,
+				ScoreDelta: -entry.Score,
+				Dropped:    true,
+			})
+		}
+	}
+	return movements
+}
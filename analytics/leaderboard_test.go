@@ -0,0 +1,159 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/pierods/gisqus"
+)
+
+func user(id, username string, posts int, rep float32) *gisqus.User {
+	u := &gisqus.User{NumPosts: posts, Rep: rep}
+	u.ID = id
+	u.Username = username
+	return u
+}
+
+func TestRankUsers(t *testing.T) {
+
+	users := []*gisqus.User{
+		user("1", "carol", 10, 1.5),
+		user("2", "alice", 30, 0.5),
+		user("3", "bob", 10, 2.5),
+		user("4", "dave", 5, 3.5),
+	}
+
+	lb := RankUsers(users, ByPosts, 3)
+	if len(lb.Entries) != 3 {
+		t.Fatal("Should keep the first 3 users")
+	}
+	if lb.Entries[0].User.ID != "2" || lb.Entries[0].Rank != 1 || lb.Entries[0].Score != 30 {
+		t.Fatal("Should rank alice first")
+	}
+	if lb.Entries[1].User.ID != "3" || lb.Entries[2].User.ID != "1" {
+		t.Fatal("Should break ties by username")
+	}
+	if lb.Entries[1].Rank != 2 || lb.Entries[2].Rank != 2 {
+		t.Fatal("Should give tied users the same rank")
+	}
+
+	lb = RankUsers(users, ByReputation, 0)
+	if len(lb.Entries) != 4 || lb.Entries[0].User.ID != "4" || lb.Entries[3].User.ID != "2" {
+		t.Fatal("Should rank by reputation")
+	}
+}
+
+func TestDiffLeaderboards(t *testing.T) {
+
+	before := RankUsers([]*gisqus.User{
+		user("1", "alice", 30, 0),
+		user("2", "bob", 20, 0),
+		user("3", "carol", 10, 0),
+	}, ByPosts, 0)
+	after := RankUsers([]*gisqus.User{
+		user("2", "bob", 40, 0),
+		user("1", "alice", 35, 0),
+		user("4", "dave", 15, 0),
+	}, ByPosts, 0)
+
+	movements := DiffLeaderboards(before, after)
+	if len(movements) != 4 {
+		t.Fatal("Should report 4 movements")
+	}
+	bob := movements[0]
+	if bob.UserID != "2" || bob.OldRank != 2 || bob.NewRank != 1 || bob.Change != 1 || bob.ScoreDelta != 20 {
+		t.Fatal("Should report bob moving up")
+	}
+	alice := movements[1]
+	if alice.Change != -1 || alice.ScoreDelta != 5 {
+		t.Fatal("Should report alice moving down")
+	}
+	if !movements[2].Entered || movements[2].UserID != "4" || movements[2].OldRank != 0 {
+		t.Fatal("Should report dave entering")
+	}
+	if !movements[3].Dropped || movements[3].UserID != "3" || movements[3].NewRank != 0 {
+		t.Fatal("Should report carol dropping out")
+	}
+}
+
+func TestBuildLeaderboard(t *testing.T) {
+
+	details := map[string]string{
+		"1": `{"code":0,"response":{"id":"1","username":"alice","numPosts":10,"numFollowers":7}}`,
+		"2": `{"code":0,"response":{"id":"2","username":"bob","numPosts":20,"numFollowers":3}}`,
+		"3": `{"code":0,"response":{"id":"3","username":"carol","numPosts":30,"numFollowers":5}}`,
+	}
+	bodies := map[string]map[string]string{
+		"forums/listMostActiveUsers": {
+			"tmz": `{"code":0,"response":[{"id":"1"},{"id":"2"}]}`,
+			"cnn": `{"code":0,"response":[{"id":"2"},{"id":"9","isAnonymous":true}]}`,
+		},
+		"forums/listMostLikedUsers": {
+			"tmz": `{"code":0,"response":[{"id":"1"}]}`,
+			"cnn": `{"code":0,"response":[{"id":"3"}]}`,
+		},
+	}
+	g := gisqus.NewGisqus("secret")
+	g.Use(func(next gisqus.Doer) gisqus.Doer {
+		return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+			if req.Endpoint == "users/details" {
+				return &gisqus.Response{StatusCode: 200, Body: []byte(details[req.Values.Get("user")])}, nil
+			}
+			return &gisqus.Response{StatusCode: 200, Body: []byte(bodies[req.Endpoint][req.Values.Get("forum")])}, nil
+		})
+	})
+
+	lb, err := BuildLeaderboard(context.Background(), &g, []string{"tmz", "cnn"}, ByFollowers, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lb.Entries) != 2 || len(lb.Forums) != 2 || lb.Metric != ByFollowers {
+		t.Fatal("Should build a leaderboard of 2 users")
+	}
+	if lb.Entries[0].User.Username != "alice" || lb.Entries[1].User.Username != "carol" {
+		t.Fatal("Should rank users of all forums by followers")
+	}
+}
+
+func TestBuildLeaderboardFailures(t *testing.T) {
+
+	failing := map[string]bool{"2": true}
+	g := gisqus.NewGisqus("secret")
+	g.Use(func(next gisqus.Doer) gisqus.Doer {
+		return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+			var body string
+			switch req.Endpoint {
+			case "forums/listMostActiveUsers", "forums/listMostLikedUsers":
+				body = `{"code":0,"cursor":{"hasNext":false},"response":[{"id":"1"},{"id":"2"}]}`
+			case "users/details":
+				id := req.Values.Get("user")
+				if failing[id] {
+					return nil, errors.New("connection refused")
+				}
+				body = `{"code":0,"response":{"id":"` + id + `","username":"alice","numPosts":10}}`
+			}
+			return &gisqus.Response{StatusCode: 200, Body: []byte(body)}, nil
+		})
+	})
+
+	lb, err := BuildLeaderboard(context.Background(), &g, []string{"tmz"}, ByPosts, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(lb.Entries) != 1 || lb.Entries[0].User.ID != "1" {
+		t.Fatal("Should rank the users whose details were fetched")
+	}
+	if len(lb.Missing) != 1 || lb.Missing[0] != "2" {
+		t.Fatal("Should list the users whose details could not be fetched")
+	}
+
+	failing["1"] = true
+	_, err = BuildLeaderboard(context.Background(), &g, []string{"tmz"}, ByPosts, 0)
+	if err == nil {
+		t.Fatal("Should fail when no details can be fetched")
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"github.com/pierods/gisqus"
+)
+
+// Kinds of the edges of a co-commenting network
+const (
+	EdgeCoThread = "coThread"
+	EdgeReply    = "reply"
+)
+
+/*
+CommentNetwork builds an undirected, weighted graph of users commenting in the same places. Two users are linked by an
+edge of kind EdgeCoThread weighing the number of threads both commented on, and by an edge of kind EdgeReply weighing the
+number of direct replies (through Post.Parent) between them. User nodes carry the username as label, and the number of
+posts and threads of the user as the "posts" and "threads" attributes.
+
+Anonymous posts, and posts without an author (e.g. deleted ones), are left out, since they cannot be tied to a user.
+*/
+type CommentNetwork struct {
+	Graph   *Graph
+	posts   map[string]int
+	threads map[string]int
+}
+
+// NewCommentNetwork returns an empty network
+func NewCommentNetwork() *CommentNetwork {
+	return &CommentNetwork{
+		Graph:   NewGraph(false),
+		posts:   make(map[string]int),
+		threads: make(map[string]int),
+	}
+}
+
+/*
+AddThread adds the posts of a thread to the network. All the posts of a thread must be added at once, for replies to be
+tied to the author of the post they reply to. Users commenting on the same thread are linked pairwise, so the cost of a
+thread grows with the square of its participants.
+*/
+func (n *CommentNetwork) AddThread(posts []*gisqus.Post) {
+
+	authors := make(map[string]string, len(posts))
+	var participants []string
+	seen := make(map[string]bool)
+	for _, post := range posts {
+		key, anonymous := authorKey(post.Author)
+		if key == "" || anonymous {
+			continue
+		}
+		authors[post.ID] = key
+		node := n.Graph.AddNode(UserNodeID(key), NodeUser, post.Author.Username)
+		n.posts[key]++
+		node.Attributes["posts"] = strconv.Itoa(n.posts[key])
+		if !seen[key] {
+			seen[key] = true
+			participants = append(participants, key)
+			n.threads[key]++
+			node.Attributes["threads"] = strconv.Itoa(n.threads[key])
+		}
+	}
+
+	for i, a := range participants {
+		for _, b := range participants[i+1:] {
+			n.Graph.AddEdge(UserNodeID(a), UserNodeID(b), EdgeCoThread, 1)
+		}
+	}
+
+	for _, post := range posts {
+		author, ok := authors[post.ID]
+		if !ok || post.Parent == 0 {
+			continue
+		}
+		parent, ok := authors[strconv.Itoa(post.Parent)]
+		if !ok || parent == author {
+			continue
+		}
+		n.Graph.AddEdge(UserNodeID(author), UserNodeID(parent), EdgeReply, 1)
+	}
+}
+
+/*
+ForumCommentNetwork builds the co-commenting network of a forum, listing its threads with ForumThreads and their posts
+with ThreadPosts. values are passed to ForumThreads, and can be used to select threads (e.g. since). At most maxThreads
+threads are analyzed (all if maxThreads <= 0).
+*/
+func ForumCommentNetwork(ctx context.Context, g *gisqus.Gisqus, forum string, values url.Values, maxThreads int) (*CommentNetwork, error) {
+
+	var threadIDs []string
+	for thread, err := range g.ForumThreadsAll(ctx, forum, values) {
+		if err != nil {
+			return nil, err
+		}
+		if maxThreads > 0 && len(threadIDs) == maxThreads {
+			break
+		}
+		threadIDs = append(threadIDs, thread.ID)
+	}
+
+	network := NewCommentNetwork()
+	for _, threadID := range threadIDs {
+		values := url.Values{}
+		values.Set("limit", "100")
+		var posts []*gisqus.Post
+		for post, err := range g.ThreadPostsAll(ctx, threadID, values) {
+			if err != nil {
+				return nil, err
+			}
+			posts = append(posts, post)
+		}
+		network.AddThread(posts)
+	}
+	return network, nil
+}
@@ -0,0 +1,80 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pierods/gisqus"
+)
+
+func TestCommentNetwork(t *testing.T) {
+
+	alice := &gisqus.PostAuthor{ID: "1", Username: "alice"}
+	bob := &gisqus.PostAuthor{ID: "2", Username: "bob"}
+	carol := &gisqus.PostAuthor{ID: "3", Username: "carol"}
+	anonymous := &gisqus.PostAuthor{IsAnonymous: true, Name: "guest"}
+
+	n := NewCommentNetwork()
+	n.AddThread([]*gisqus.Post{
+		post("10", 0, 0, alice),
+		post("11", 10, 1, bob),
+		post("12", 11, 2, alice),
+		post("13", 12, 3, alice),
+		post("14", 10, 4, anonymous),
+	})
+	n.AddThread([]*gisqus.Post{
+		post("20", 0, 0, bob),
+		post("21", 0, 1, alice),
+		post("22", 21, 2, carol),
+	})
+
+	if len(n.Graph.Nodes()) != 3 {
+		t.Fatal("Should leave anonymous users out")
+	}
+	if n.Graph.Node(UserNodeID("1")).Attributes["posts"] != "4" || n.Graph.Node(UserNodeID("1")).Attributes["threads"] != "2" {
+		t.Fatal("Should count posts and threads of users")
+	}
+	weights := make(map[string]float64)
+	for _, edge := range n.Graph.Edges() {
+		weights[edge.From+" "+edge.To+" "+edge.Kind] = edge.Weight
+	}
+	if weights["user:1 user:2 coThread"] != 2 || weights["user:1 user:3 coThread"] != 1 || weights["user:2 user:3 coThread"] != 1 {
+		t.Fatal("Should count shared threads")
+	}
+	if weights["user:1 user:2 reply"] != 2 || weights["user:1 user:3 reply"] != 1 {
+		t.Fatal("Should count replies, ignoring self replies")
+	}
+	if len(weights) != 5 {
+		t.Fatal("Should not add other edges")
+	}
+}
+
+func TestForumCommentNetwork(t *testing.T) {
+
+	g := fakeDisqus(map[string]string{
+		"forums/listThreads": `{"code":0,"cursor":{"hasNext":false},"response":[{"id":"1"},{"id":"2"}]}`,
+		"threads/listPosts": `{"code":0,"cursor":{"hasNext":false},"response":[
+			{"id":"10","author":{"id":"1","username":"alice"}},
+			{"id":"11","parent":10,"author":{"id":"2","username":"bob"}}]}`,
+	})
+
+	n, err := ForumCommentNetwork(context.Background(), g, "tmz", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges := n.Graph.Edges()
+	if len(edges) != 2 || edges[0].Weight != 2 || edges[1].Kind != EdgeReply || edges[1].Weight != 2 {
+		t.Fatal("Should build the network of all threads")
+	}
+
+	n, err = ForumCommentNetwork(context.Background(), g, "tmz", nil, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n.Graph.Edges()[0].Weight != 1 {
+		t.Fatal("Should stop at maxThreads")
+	}
+}
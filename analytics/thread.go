@@ -0,0 +1,133 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+// Package analytics computes statistics over data returned by gisqus
+package analytics
+
+import (
+	"sort"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// ThreadStats models the engagement statistics of a thread
+type ThreadStats struct {
+	Posts int
+	// DepthDistribution counts posts by reply depth (0 for top level posts)
+	DepthDistribution map[int]int
+	// UniqueParticipants counts distinct authors. Anonymous authors are told apart by name
+	UniqueParticipants int
+	// FirstPost and LastPost are the creation times of the oldest and newest posts
+	FirstPost time.Time
+	LastPost  time.Time
+	// PostsPerHour is the average number of posts per hour between the first and the last post
+	PostsPerHour float64
+	// HourlyPosts counts posts by hour of creation
+	HourlyPosts map[time.Time]int
+	// MedianTimeToFirstReply is the median, over posts having replies, of the time elapsed before the first reply
+	MedianTimeToFirstReply time.Duration
+	Likes                  int
+	Dislikes               int
+	// LikeRatio is Likes / (Likes + Dislikes), 0 without votes
+	LikeRatio float64
+	// AnonymousShare is the share of posts written by anonymous authors
+	AnonymousShare float64
+}
+
+/*
+ThreadEngagement computes the engagement statistics of a thread from its posts, e.g. all the pages returned by
+gisqus.ThreadPosts.
+*/
+func ThreadEngagement(posts []*gisqus.Post) *ThreadStats {
+
+	stats := &ThreadStats{
+		Posts:             len(posts),
+		DepthDistribution: make(map[int]int),
+		HourlyPosts:       make(map[time.Time]int),
+	}
+	if len(posts) == 0 {
+		return stats
+	}
+
+	participants := make(map[string]bool)
+	anonymous := 0
+	stats.FirstPost = posts[0].CreatedAt
+	stats.LastPost = posts[0].CreatedAt
+
+	for _, post := range posts {
+		if post.CreatedAt.Before(stats.FirstPost) {
+			stats.FirstPost = post.CreatedAt
+		}
+		if post.CreatedAt.After(stats.LastPost) {
+			stats.LastPost = post.CreatedAt
+		}
+		stats.HourlyPosts[post.CreatedAt.Truncate(time.Hour)]++
+		stats.Likes += post.Likes
+		stats.Dislikes += post.Dislikes
+		if key, anon := authorKey(post.Author); key != "" {
+			participants[key] = true
+			if anon {
+				anonymous++
+			}
+		}
+	}
+	stats.UniqueParticipants = len(participants)
+	stats.AnonymousShare = float64(anonymous) / float64(len(posts))
+	if stats.Likes+stats.Dislikes > 0 {
+		stats.LikeRatio = float64(stats.Likes) / float64(stats.Likes+stats.Dislikes)
+	}
+	hours := stats.LastPost.Sub(stats.FirstPost).Hours()
+	if hours < 1 {
+		hours = 1
+	}
+	stats.PostsPerHour = float64(len(posts)) / hours
+
+	var firstReplies []time.Duration
+	gisqus.BuildThreadTree(posts).Walk(func(node *gisqus.ThreadNode) bool {
+		stats.DepthDistribution[node.Depth]++
+		if len(node.Children) > 0 {
+			first := node.Children[0].Post.CreatedAt
+			for _, child := range node.Children {
+				if child.Post.CreatedAt.Before(first) {
+					first = child.Post.CreatedAt
+				}
+			}
+			elapsed := first.Sub(node.Post.CreatedAt)
+			if elapsed < 0 {
+				elapsed = 0
+			}
+			firstReplies = append(firstReplies, elapsed)
+		}
+		return true
+	})
+	stats.MedianTimeToFirstReply = median(firstReplies)
+
+	return stats
+}
+
+// authorKey identifies the author of a post, and tells whether it is anonymous
+func authorKey(author *gisqus.PostAuthor) (string, bool) {
+
+	switch {
+	case author == nil:
+		return "", false
+	case author.IsAnonymous:
+		return "anonymous:" + author.Name, true
+	default:
+		return author.ID, false
+	}
+}
+
+func median(durations []time.Duration) time.Duration {
+
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	middle := len(durations) / 2
+	if len(durations)%2 == 1 {
+		return durations[middle]
+	}
+	return (durations[middle-1] + durations[middle]) / 2
+}
@@ -0,0 +1,69 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+var start = time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+
+func post(id string, parent int, minutes int, author *gisqus.PostAuthor) *gisqus.Post {
+	p := &gisqus.Post{Parent: parent}
+	p.ID = id
+	p.CreatedAt = start.Add(time.Duration(minutes) * time.Minute)
+	p.Author = author
+	return p
+}
+
+func TestThreadEngagement(t *testing.T) {
+
+	alice := &gisqus.PostAuthor{ID: "1"}
+	bob := &gisqus.PostAuthor{ID: "2"}
+	anon := &gisqus.PostAuthor{IsAnonymous: true, Name: "guest"}
+
+	posts := []*gisqus.Post{
+		post("10", 0, 0, alice),
+		post("11", 10, 10, bob),
+		post("12", 10, 30, anon),
+		post("13", 11, 40, alice),
+		post("14", 0, 150, bob),
+	}
+	posts[0].Likes = 3
+	posts[1].Likes = 1
+	posts[2].Dislikes = 4
+
+	stats := ThreadEngagement(posts)
+
+	if stats.Posts != 5 || stats.UniqueParticipants != 3 {
+		t.Fatal("Should count posts and participants")
+	}
+	if stats.DepthDistribution[0] != 2 || stats.DepthDistribution[1] != 2 || stats.DepthDistribution[2] != 1 {
+		t.Fatal("Should compute the reply depth distribution", stats.DepthDistribution)
+	}
+	if !stats.FirstPost.Equal(start) || stats.PostsPerHour != 2 {
+		t.Fatal("Should compute posts per hour", stats.PostsPerHour)
+	}
+	if stats.HourlyPosts[start] != 4 || stats.HourlyPosts[start.Add(2*time.Hour)] != 1 {
+		t.Fatal("Should count posts by hour")
+	}
+	// post 10 was first replied after 10 minutes, post 11 after 30
+	if stats.MedianTimeToFirstReply != 20*time.Minute {
+		t.Fatal("Should compute the median time to first reply", stats.MedianTimeToFirstReply)
+	}
+	if stats.Likes != 4 || stats.Dislikes != 4 || stats.LikeRatio != 0.5 {
+		t.Fatal("Should compute the like ratio")
+	}
+	if stats.AnonymousShare != 0.2 {
+		t.Fatal("Should compute the share of anonymous authors")
+	}
+
+	empty := ThreadEngagement(nil)
+	if empty.Posts != 0 || empty.LikeRatio != 0 || empty.MedianTimeToFirstReply != 0 {
+		t.Fatal("Should handle threads without posts")
+	}
+}
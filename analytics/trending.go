@@ -0,0 +1,258 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"math"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// TrendMetric represents the quantity of a thread that is checked for anomalies
+type TrendMetric string
+
+/*
+TrendMetric constants. PostVelocity is the number of posts per hour a thread received between two polls, Score is the
+trending score reported by ThreadTrending (threads only listed by ThreadHot have no score).
+*/
+const (
+	PostVelocity TrendMetric = "postVelocity"
+	Score        TrendMetric = "score"
+)
+
+const defaultTrendWindow = 12
+
+// Anomaly models a thread whose metric deviates from its recent history
+type Anomaly struct {
+	Thread *gisqus.Thread `json:"thread"`
+	Metric TrendMetric    `json:"metric"`
+	At     time.Time      `json:"at"`
+	Value  float64        `json:"value"`
+	Mean   float64        `json:"mean"`
+	StdDev float64        `json:"stdDev"`
+	ZScore float64        `json:"zScore"`
+}
+
+/*
+TrendDetector polls ThreadTrending and ThreadHot, keeps a rolling history of the last Window observations of every
+thread, and calls OnAnomaly when the post velocity or the score of a thread is more than Threshold standard deviations
+away from the mean of its history. A thread is checked once at least MinSamples observations have been collected; threads
+that are not listed for Window polls are forgotten. A Window below 1 is taken as the default of 12.
+
+Values are passed to both endpoints (e.g. forum, limit). Errors met while polling are passed to OnError, if set, and
+polling goes on.
+*/
+type TrendDetector struct {
+	gisqus     *gisqus.Gisqus
+	Interval   time.Duration
+	Window     int
+	MinSamples int
+	Threshold  float64
+	Values     url.Values
+	OnAnomaly  func(*Anomaly)
+	OnError    func(error)
+
+	mu      sync.Mutex
+	polls   int
+	history map[string]*trendHistory
+}
+
+type trendSample struct {
+	at       time.Time
+	posts    int
+	score    float64
+	hasScore bool
+}
+
+type trendHistory struct {
+	samples    []trendSample
+	velocities []float64
+	scores     []float64
+	lastPoll   int
+}
+
+/*
+NewTrendDetector returns a TrendDetector polling every 5 minutes, with a history of 12 observations, checking threads
+after 4 observations, and flagging deviations beyond 3 standard deviations.
+*/
+func NewTrendDetector(g *gisqus.Gisqus, onAnomaly func(*Anomaly)) *TrendDetector {
+	return &TrendDetector{
+		gisqus:     g,
+		Interval:   5 * time.Minute,
+		Window:     defaultTrendWindow,
+		MinSamples: 4,
+		Threshold:  3,
+		Values:     url.Values{},
+		OnAnomaly:  onAnomaly,
+		history:    make(map[string]*trendHistory),
+	}
+}
+
+// Run polls until ctx is done, and returns ctx's error
+func (d *TrendDetector) Run(ctx context.Context) error {
+
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+	for {
+		err := d.Poll(ctx)
+		if err != nil && d.OnError != nil && ctx.Err() == nil {
+			d.OnError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Poll fetches trending and hot threads once, and records them with Observe
+func (d *TrendDetector) Poll(ctx context.Context) error {
+
+	trending, err := d.gisqus.ThreadTrending(ctx, d.Values)
+	if err != nil {
+		return err
+	}
+	hot, err := d.gisqus.ThreadHot(ctx, d.Values)
+	if err != nil {
+		return err
+	}
+	d.Observe(time.Now(), trending.Response, hot.Response)
+	return nil
+}
+
+/*
+Observe records the trending and hot threads seen at a given time, and checks them for anomalies. Threads listed by
+both are recorded once, with the score of their trend.
+*/
+func (d *TrendDetector) Observe(at time.Time, trends []*gisqus.Trend, hot []*gisqus.Thread) {
+
+	d.mu.Lock()
+	d.polls++
+	if d.history == nil {
+		d.history = make(map[string]*trendHistory)
+	}
+
+	var threads []*gisqus.Thread
+	samples := make(map[string]trendSample)
+	for _, trend := range trends {
+		thread := trend.TrendingThread
+		if thread == nil {
+			continue
+		}
+		if _, ok := samples[thread.ID]; !ok {
+			threads = append(threads, thread)
+		}
+		samples[thread.ID] = trendSample{at: at, posts: thread.Posts, score: float64(trend.Score), hasScore: true}
+	}
+	for _, thread := range hot {
+		if _, ok := samples[thread.ID]; !ok {
+			threads = append(threads, thread)
+			samples[thread.ID] = trendSample{at: at, posts: thread.Posts}
+		}
+	}
+
+	var anomalies []*Anomaly
+	for _, thread := range threads {
+		h := d.history[thread.ID]
+		if h == nil {
+			h = &trendHistory{}
+			d.history[thread.ID] = h
+		}
+		h.lastPoll = d.polls
+		anomalies = append(anomalies, d.record(h, thread, samples[thread.ID])...)
+	}
+	for id, h := range d.history {
+		if d.polls-h.lastPoll >= d.window() {
+			delete(d.history, id)
+		}
+	}
+	d.mu.Unlock()
+
+	if d.OnAnomaly != nil {
+		for _, anomaly := range anomalies {
+			d.OnAnomaly(anomaly)
+		}
+	}
+}
+
+func (d *TrendDetector) window() int {
+
+	if d.Window < 1 {
+		return defaultTrendWindow
+	}
+	return d.Window
+}
+
+func (d *TrendDetector) record(h *trendHistory, thread *gisqus.Thread, sample trendSample) []*Anomaly {
+
+	window := d.window()
+	var anomalies []*Anomaly
+	check := func(metric TrendMetric, series []float64, value float64) {
+		if len(series) < d.MinSamples {
+			return
+		}
+		mean, stdDev := meanStdDev(series)
+		if stdDev == 0 {
+			return
+		}
+		z := (value - mean) / stdDev
+		if math.Abs(z) >= d.Threshold {
+			anomalies = append(anomalies, &Anomaly{
+				Thread: thread,
+				Metric: metric,
+				At:     sample.at,
+				Value:  value,
+				Mean:   mean,
+				StdDev: stdDev,
+				ZScore: z,
+			})
+		}
+	}
+
+	if n := len(h.samples); n > 0 {
+		prev := h.samples[n-1]
+		if hours := sample.at.Sub(prev.at).Hours(); hours > 0 {
+			velocity := float64(sample.posts-prev.posts) / hours
+			check(PostVelocity, h.velocities, velocity)
+			h.velocities = appendWindow(h.velocities, velocity, window)
+		}
+	}
+	if sample.hasScore {
+		check(Score, h.scores, sample.score)
+		h.scores = appendWindow(h.scores, sample.score, window)
+	}
+	h.samples = append(h.samples, sample)
+	if len(h.samples) > window {
+		h.samples = h.samples[len(h.samples)-window:]
+	}
+	return anomalies
+}
+
+func appendWindow(series []float64, value float64, window int) []float64 {
+
+	series = append(series, value)
+	if len(series) > window {
+		series = series[len(series)-window:]
+	}
+	return series
+}
+
+func meanStdDev(series []float64) (float64, float64) {
+
+	mean := 0.0
+	for _, v := range series {
+		mean += v
+	}
+	mean /= float64(len(series))
+	variance := 0.0
+	for _, v := range series {
+		variance += (v - mean) * (v - mean)
+	}
+	return mean, math.Sqrt(variance / float64(len(series)))
+}
@@ -0,0 +1,85 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+func trend(id string, posts int, score float32) *gisqus.Trend {
+	thread := &gisqus.Thread{ID: id, Posts: posts}
+	return &gisqus.Trend{TrendingThread: thread, Score: score}
+}
+
+func TestTrendDetector(t *testing.T) {
+
+	var anomalies []*Anomaly
+	d := NewTrendDetector(nil, func(a *Anomaly) {
+		anomalies = append(anomalies, a)
+	})
+
+	// thread 1 gets 10 or 12 posts every hour, then 100; thread 2 is only hot, at a steady pace
+	posts := []int{0, 10, 22, 32, 44, 144}
+	for i, p := range posts {
+		d.Observe(start.Add(time.Duration(i)*time.Hour), []*gisqus.Trend{trend("1", p, float32(1+i%2))}, []*gisqus.Thread{{ID: "2", Posts: 5 * i}})
+		if i < len(posts)-1 && len(anomalies) != 0 {
+			t.Fatal("Should not flag regular activity")
+		}
+	}
+	if len(anomalies) != 1 {
+		t.Fatal("Should flag the comment storm")
+	}
+	a := anomalies[0]
+	if a.Thread.ID != "1" || a.Metric != PostVelocity || a.Value != 100 || a.Mean != 11 || a.StdDev != 1 || a.ZScore != 89 {
+		t.Fatal("Should report the deviation of post velocity")
+	}
+
+	d.Observe(start.Add(6*time.Hour), []*gisqus.Trend{trend("1", 164, 50)}, nil)
+	if len(anomalies) != 2 || anomalies[1].Metric != Score {
+		t.Fatal("Should flag score deviations")
+	}
+
+	for i := 0; i < d.Window; i++ {
+		d.Observe(start.Add(time.Duration(7+i)*time.Hour), nil, nil)
+	}
+	if len(d.history) != 0 {
+		t.Fatal("Should forget threads no longer listed")
+	}
+}
+
+func TestTrendDetectorPoll(t *testing.T) {
+
+	g := fakeDisqus(map[string]string{
+		"trends/listThreads": `{"code":0,"response":[{"score":1.5,"thread":{"id":"1","posts":10}}]}`,
+		"threads/listHot":    `{"code":0,"response":[{"id":"1","posts":10},{"id":"2","posts":3}]}`,
+	})
+	d := NewTrendDetector(g, nil)
+	err := d.Poll(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.history) != 2 || d.history["1"].scores[0] != 1.5 || len(d.history["2"].scores) != 0 {
+		t.Fatal("Should record trending and hot threads")
+	}
+}
+
+func TestTrendDetectorNoWindow(t *testing.T) {
+
+	d := NewTrendDetector(nil, nil)
+	d.Window = 0
+	for i := 0; i < 20; i++ {
+		d.Observe(start.Add(time.Duration(i)*time.Hour), []*gisqus.Trend{trend("1", 10*i, 1)}, nil)
+	}
+	h := d.history["1"]
+	if h == nil {
+		t.Fatal("Should keep history without a window")
+	}
+	if len(h.samples) != defaultTrendWindow || len(h.velocities) != defaultTrendWindow {
+		t.Fatal("Should use the default window")
+	}
+}
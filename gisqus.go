@@ -5,14 +5,25 @@
 package gisqus
 
 import (
+	"log/slog"
+	"net/http"
 	"strings"
+	"sync"
 	"time"
 )
 
 // Gisqus is lib's entry point
 type Gisqus struct {
-	secret string
-	limits DisqusRateLimit
+	keys         *keyPool
+	limitsMu     *sync.Mutex
+	limits       DisqusRateLimit
+	responseHook func(*RawResponse)
+	middleware   []Middleware
+	logger       *slog.Logger
+	cache        Cache
+	cacheTTLs    map[string]time.Duration
+	flights      *flightGroup
+	breaker      *breaker
 }
 
 /*
@@ -20,7 +31,8 @@ NewGisqus returns a new instance of Gisqus. secret is Disqus' API key
 */
 func NewGisqus(secret string) Gisqus {
 	return Gisqus{
-		secret: secret,
+		keys:     newKeyPool([]string{secret}),
+		limitsMu: &sync.Mutex{},
 	}
 }
 
@@ -81,12 +93,24 @@ func (g *Gisqus) SetPostsURLs(pu PostsURLS) {
 }
 
 /*
-Limits return the current rate limits for the user account
+Limits return the current rate limits for the user account, as reported by the last call made to Disqus
 */
 func (g *Gisqus) Limits() DisqusRateLimit {
+	g.limitsMu.Lock()
+	defer g.limitsMu.Unlock()
 	return g.limits
 }
 
+/*
+SetResponseHook registers a function that is called with the raw response of every call made to Disqus, before it is inflated.
+The api secret is redacted from the URL passed to the hook. For streamed calls (e.g. PostListStream) the hook is called
+when the stream is closed, with the part of the body that was read: to do so the streamed body is kept in memory, so
+a hook gives up the memory bound of streaming. A nil hook disables the feature.
+*/
+func (g *Gisqus) SetResponseHook(hook func(*RawResponse)) {
+	g.responseHook = hook
+}
+
 /*
 ToDisqusTime returns a string that can be used in Disqus call for timedate parameters
 */
@@ -128,6 +152,14 @@ type DisqusRateLimit struct {
 	RatelimitReset     time.Time
 }
 
+// RawResponse holds the exact data returned by Disqus for a call
+type RawResponse struct {
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
 // ResponseStub is the standard Disqus response stub
 type ResponseStub struct {
 	Code int `json:"code"`
@@ -0,0 +1,170 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned (wrapped) by calls rejected because Disqus has been failing on their endpoint
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState represents the state of the circuit breaker of an endpoint
+type CircuitState int
+
+// Circuit states. Calls go through when closed, are rejected when open, and are let through as trials when half open.
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+// CircuitBreakerSettings configures the circuit breaker
+type CircuitBreakerSettings struct {
+	// FailureThreshold is the number of consecutive failures that opens the circuit of an endpoint
+	FailureThreshold int
+	// OpenTimeout is how long a circuit stays open before trial calls are let through
+	OpenTimeout time.Duration
+	// HalfOpenMaxCalls is the number of trial calls allowed at the same time when half open
+	HalfOpenMaxCalls int
+}
+
+/*
+SetCircuitBreaker protects Disqus (and the quota) from calls that are bound to fail. Every endpoint has its own
+circuit: after FailureThreshold consecutive failures (network errors, 429 and 5xx responses) calls to the endpoint fail
+immediately with ErrCircuitOpen for OpenTimeout, then up to HalfOpenMaxCalls trial calls are let through. A successful
+trial closes the circuit, a failed one opens it again. Responses served from cache are not affected, and a call shared by
+coalesced callers (see SetCoalescing) counts once.
+A nil settings disables the circuit breaker.
+*/
+func (g *Gisqus) SetCircuitBreaker(settings *CircuitBreakerSettings) {
+	if settings == nil {
+		g.breaker = nil
+		return
+	}
+	s := *settings
+	if s.FailureThreshold < 1 {
+		s.FailureThreshold = 1
+	}
+	if s.HalfOpenMaxCalls < 1 {
+		s.HalfOpenMaxCalls = 1
+	}
+	g.breaker = &breaker{
+		settings: s,
+		circuits: make(map[string]*circuit),
+	}
+}
+
+/*
+CircuitState returns the state of the circuit of endpoint (e.g. "threads/details"). It returns CircuitClosed when the
+circuit breaker is disabled.
+*/
+func (g *Gisqus) CircuitState(endpoint string) CircuitState {
+	if g.breaker == nil {
+		return CircuitClosed
+	}
+	g.breaker.mu.Lock()
+	defer g.breaker.mu.Unlock()
+	return g.breaker.circuit(endpoint).current(g.breaker.settings, time.Now())
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	trials   int
+}
+
+func (c *circuit) current(settings CircuitBreakerSettings, now time.Time) CircuitState {
+	if c.state == CircuitOpen && now.Sub(c.openedAt) >= settings.OpenTimeout {
+		c.state = CircuitHalfOpen
+		c.trials = 0
+	}
+	return c.state
+}
+
+type breaker struct {
+	settings CircuitBreakerSettings
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+func (b *breaker) circuit(endpoint string) *circuit {
+	c, ok := b.circuits[endpoint]
+	if !ok {
+		c = &circuit{}
+		b.circuits[endpoint] = c
+	}
+	return c
+}
+
+func (b *breaker) allow(endpoint string) bool {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(endpoint)
+	switch c.current(b.settings, time.Now()) {
+	case CircuitOpen:
+		return false
+	case CircuitHalfOpen:
+		if c.trials >= b.settings.HalfOpenMaxCalls {
+			return false
+		}
+		c.trials++
+	}
+	return true
+}
+
+func (b *breaker) record(endpoint string, failed bool) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(endpoint)
+	if !failed {
+		c.state = CircuitClosed
+		c.failures = 0
+		return
+	}
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= b.settings.FailureThreshold {
+		c.state = CircuitOpen
+		c.openedAt = time.Now()
+	}
+}
+
+func (b *breaker) middleware(next Doer) Doer {
+
+	return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+		if !b.allow(req.Endpoint) {
+			return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, req.Endpoint)
+		}
+		resp, err := next.Do(ctx, req)
+		if err != nil && (ctx.Err() != nil || errors.Is(err, ErrQuotaExhausted)) {
+			// cancelled by the caller or not sent at all, Disqus is not to blame
+			b.release(req.Endpoint)
+			return resp, err
+		}
+		b.record(req.Endpoint, err != nil && (resp == nil || resp.StatusCode == 429 || resp.StatusCode >= 500))
+		return resp, err
+	})
+}
+
+// release gives back a trial call that did not reach a verdict
+func (b *breaker) release(endpoint string) {
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.circuit(endpoint)
+	if c.state == CircuitHalfOpen && c.trials > 0 {
+		c.trials--
+	}
+}
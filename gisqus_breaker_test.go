@@ -0,0 +1,108 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+
+	failing := true
+	calls := 0
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			calls++
+			if failing {
+				return nil, errors.New("connection refused")
+			}
+			return next.Do(ctx, req)
+		})
+	})
+	g.SetCircuitBreaker(&CircuitBreakerSettings{
+		FailureThreshold: 2,
+		OpenTimeout:      50 * time.Millisecond,
+	})
+
+	for i := 0; i < 2; i++ {
+		_, err := g.ThreadDetails(testCtx, "5843656825", url.Values{})
+		if err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatal("Should let calls through while closed")
+		}
+	}
+	if g.CircuitState("threads/details") != CircuitOpen {
+		t.Fatal("Should open the circuit after consecutive failures")
+	}
+	_, err := g.ThreadDetails(testCtx, "5843656825", url.Values{})
+	if !errors.Is(err, ErrCircuitOpen) || calls != 2 {
+		t.Fatal("Should reject calls while open")
+	}
+	if g.CircuitState("threads/list") != CircuitClosed {
+		t.Fatal("Should keep a circuit per endpoint")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	if g.CircuitState("threads/details") != CircuitHalfOpen {
+		t.Fatal("Should be half open after the open timeout")
+	}
+	_, err = g.ThreadDetails(testCtx, "5843656825", url.Values{})
+	if err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatal("Should let a trial call through while half open")
+	}
+	if g.CircuitState("threads/details") != CircuitOpen {
+		t.Fatal("Should open the circuit again after a failed trial")
+	}
+
+	time.Sleep(60 * time.Millisecond)
+	failing = false
+	_, err = g.ThreadDetails(testCtx, "5843656825", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.CircuitState("threads/details") != CircuitClosed {
+		t.Fatal("Should close the circuit after a successful trial")
+	}
+}
+
+func TestCircuitBreakerCoalescing(t *testing.T) {
+
+	release := make(chan struct{})
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			<-release
+			return nil, errors.New("connection refused")
+		})
+	})
+	g.SetCoalescing(true)
+	g.SetCircuitBreaker(&CircuitBreakerSettings{
+		FailureThreshold: 2,
+		OpenTimeout:      time.Hour,
+	})
+
+	var wg sync.WaitGroup
+	joined := make(chan struct{}, 5)
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			g.ThreadDetails(newJoinContext(joined), "5843656825", url.Values{})
+		}()
+	}
+	for i := 0; i < 4; i++ {
+		<-joined
+	}
+	close(release)
+	wg.Wait()
+
+	if g.CircuitState("threads/details") != CircuitClosed {
+		t.Fatal("Should count a failed call shared by coalesced callers once")
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"time"
+)
+
+/*
+PostDetailsMany calls PostDetails for every id in postIDs, with at most concurrency calls in flight. Failures do not
+abort the batch: posts are returned in the first map and errors in the second, both keyed by id.
+When Disqus reports that the rate limit has been exhausted, calls wait for the limit to be reset.
+*/
+func (gisqus *Gisqus) PostDetailsMany(ctx context.Context, postIDs []string, values url.Values, concurrency int) (map[string]*Post, map[string]error) {
+
+	return detailsMany(ctx, gisqus, postIDs, concurrency, func(ctx context.Context, id string) (*Post, error) {
+		pdr, err := gisqus.PostDetails(ctx, id, values)
+		if err != nil {
+			return nil, err
+		}
+		return pdr.Response, nil
+	})
+}
+
+/*
+UserDetailsMany calls UserDetails for every id in userIDs, with at most concurrency calls in flight. Failures do not
+abort the batch: users are returned in the first map and errors in the second, both keyed by id.
+When Disqus reports that the rate limit has been exhausted, calls wait for the limit to be reset.
+*/
+func (gisqus *Gisqus) UserDetailsMany(ctx context.Context, userIDs []string, values url.Values, concurrency int) (map[string]*User, map[string]error) {
+
+	return detailsMany(ctx, gisqus, userIDs, concurrency, func(ctx context.Context, id string) (*User, error) {
+		udr, err := gisqus.UserDetails(ctx, id, values)
+		if err != nil {
+			return nil, err
+		}
+		return udr.Response, nil
+	})
+}
+
+/*
+ForumDetailsMany calls ForumDetails for every id in forumIDs, with at most concurrency calls in flight. Failures do not
+abort the batch: forums are returned in the first map and errors in the second, both keyed by id.
+When Disqus reports that the rate limit has been exhausted, calls wait for the limit to be reset.
+*/
+func (gisqus *Gisqus) ForumDetailsMany(ctx context.Context, forumIDs []string, values url.Values, concurrency int) (map[string]*Forum, map[string]error) {
+
+	return detailsMany(ctx, gisqus, forumIDs, concurrency, func(ctx context.Context, id string) (*Forum, error) {
+		fdr, err := gisqus.ForumDetails(ctx, id, values)
+		if err != nil {
+			return nil, err
+		}
+		return fdr.Response, nil
+	})
+}
+
+func detailsMany[T any](ctx context.Context, g *Gisqus, ids []string, concurrency int, fetch func(context.Context, string) (T, error)) (map[string]T, map[string]error) {
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make(map[string]T)
+	errs := make(map[string]error)
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	jobs := make(chan string)
+
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for id := range jobs {
+				var result T
+				err := g.WaitForQuota(ctx)
+				if err == nil {
+					result, err = fetch(ctx, id)
+				}
+				mu.Lock()
+				if err != nil {
+					errs[id] = err
+				} else {
+					results[id] = result
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	seen := make(map[string]bool)
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			jobs <- id
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
+
+/*
+WaitForQuota blocks until the rate limit is reset, if Disqus reported the quota of every key as exhausted. It returns
+early with ctx's error if ctx is done. Programs making long series of calls (e.g. crawlers) can use it to pace
+themselves like the bulk fetchers do.
+*/
+func (g *Gisqus) WaitForQuota(ctx context.Context) error {
+
+	wait := g.keys.wait()
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
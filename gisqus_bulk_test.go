@@ -0,0 +1,60 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPostDetailsMany(t *testing.T) {
+
+	var calls int
+	g := countingGisqus(&calls)
+
+	posts, errs := g.PostDetailsMany(testCtx, []string{"3320987826", "1", "", "1"}, url.Values{}, 2)
+	if len(posts) != 2 || posts["3320987826"] == nil || posts["1"] == nil {
+		t.Fatal("Should return posts by id")
+	}
+	if len(errs) != 1 || errs[""] == nil {
+		t.Fatal("Should return errors by id without aborting the batch")
+	}
+	if calls != 2 {
+		t.Fatal("Should call Disqus once per distinct id")
+	}
+}
+
+func TestUserAndForumDetailsMany(t *testing.T) {
+
+	users, errs := testGisqus.UserDetailsMany(testCtx, []string{"79849"}, url.Values{}, 4)
+	if len(errs) != 0 || users["79849"].Username != "laross19" {
+		t.Fatal("Should be able to retrieve users by id")
+	}
+	forums, errs := testGisqus.ForumDetailsMany(testCtx, []string{"mapleleafshotstove"}, url.Values{}, 4)
+	if len(errs) != 0 || forums["mapleleafshotstove"].ID != "mapleleafshotstove" {
+		t.Fatal("Should be able to retrieve forums by id")
+	}
+}
+
+func TestWaitForQuota(t *testing.T) {
+
+	g := NewGisqus("secret")
+	key := g.keys.keys[0]
+	g.keys.update(key, DisqusRateLimit{
+		RatelimitLimit: 1000,
+		RatelimitReset: time.Now().Add(time.Hour),
+	})
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Millisecond)
+	defer cancel()
+
+	if g.WaitForQuota(ctx) == nil {
+		t.Fatal("Should wait for the rate limit to be reset")
+	}
+	key.limits.RatelimitReset = time.Now().Add(-time.Second)
+	if g.WaitForQuota(testCtx) != nil {
+		t.Fatal("Should not wait once the rate limit has been reset")
+	}
+}
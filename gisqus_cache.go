@@ -0,0 +1,252 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// defaultLRUSize is the size of LRU caches created with a size below 1
+const defaultLRUSize = 1000
+
+/*
+Cache stores successful Disqus responses, as JSON documents holding their headers and body. Implementations must be
+safe for concurrent use.
+*/
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Set(key string, body []byte, ttl time.Duration)
+}
+
+/*
+DefaultCacheTTLs are the time to live of cached responses, by endpoint. Details of forums rarely change, lists of hot
+and trending threads change all the time. Endpoints not listed are not cached.
+*/
+var DefaultCacheTTLs = map[string]time.Duration{
+	"forums/details":        24 * time.Hour,
+	"forums/listCategories": 24 * time.Hour,
+	"users/details":         time.Hour,
+	"threads/details":       10 * time.Minute,
+	"posts/details":         10 * time.Minute,
+	"threads/set":           10 * time.Minute,
+	"threads/listPopular":   5 * time.Minute,
+	"threads/listHot":       time.Minute,
+	"trends/listThreads":    time.Minute,
+}
+
+/*
+SetCache makes Gisqus serve responses from cache when possible. ttls maps endpoint names (e.g. "forums/details") to
+the time to live of their responses; endpoints not in ttls are never cached. Streamed calls (e.g. PostListStream) are
+served from cache, but do not populate it. If ttls is nil, DefaultCacheTTLs is used.
+Cache keys are made of the endpoint name and the normalized parameters of the call, the api secret is never part of
+them. A nil cache disables caching.
+*/
+func (g *Gisqus) SetCache(cache Cache, ttls map[string]time.Duration) {
+	if ttls == nil {
+		ttls = DefaultCacheTTLs
+	}
+	g.cache = cache
+	g.cacheTTLs = ttls
+}
+
+// cacheKey normalizes the parameters of a call: keys are sorted by Encode, the values of every key are sorted here
+func cacheKey(req *Request) string {
+
+	values := url.Values{}
+	for k, v := range req.Values {
+		sorted := append([]string(nil), v...)
+		sort.Strings(sorted)
+		values[k] = sorted
+	}
+	return req.Endpoint + "?" + values.Encode()
+}
+
+// cachedResponse is what is stored in a Cache
+type cachedResponse struct {
+	Header http.Header     `json:"header"`
+	Body   json.RawMessage `json:"body"`
+}
+
+func cacheResponses(cache Cache, ttls map[string]time.Duration) Middleware {
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+			ttl, ok := ttls[req.Endpoint]
+			if !ok || ttl <= 0 {
+				return next.Do(ctx, req)
+			}
+			key := cacheKey(req)
+			if data, ok := cache.Get(key); ok {
+				var cached cachedResponse
+				// entries that cannot be decoded are treated as misses
+				if json.Unmarshal(data, &cached) == nil && cached.Body != nil {
+					return &Response{
+						StatusCode: 200,
+						Header:     cached.Header,
+						Body:       cached.Body,
+					}, nil
+				}
+			}
+			resp, err := next.Do(ctx, req)
+			if err == nil && resp.Body != nil && json.Valid(resp.Body) {
+				data, err := json.Marshal(&cachedResponse{Header: resp.Header, Body: resp.Body})
+				if err == nil {
+					cache.Set(key, data, ttl)
+				}
+			}
+			return resp, err
+		})
+	}
+}
+
+// LRUCache is an in memory Cache that evicts the least recently used entries once full
+type LRUCache struct {
+	mu      sync.Mutex
+	size    int
+	entries map[string]*list.Element
+	lru     *list.List
+}
+
+type lruEntry struct {
+	key     string
+	body    []byte
+	expires time.Time
+}
+
+// NewLRUCache returns an LRUCache holding at most size responses. A size below 1 means 1000 responses.
+func NewLRUCache(size int) *LRUCache {
+
+	if size < 1 {
+		size = defaultLRUSize
+	}
+	return &LRUCache{
+		size:    size,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the response cached under key, if present and not expired
+func (c *LRUCache) Get(key string) ([]byte, bool) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*lruEntry)
+	if time.Now().After(entry.expires) {
+		c.lru.Remove(el)
+		delete(c.entries, key)
+		return nil, false
+	}
+	c.lru.MoveToFront(el)
+	return entry.body, true
+}
+
+// Set caches a response under key for ttl
+func (c *LRUCache) Set(key string, body []byte, ttl time.Duration) {
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry)
+		entry.body = body
+		entry.expires = time.Now().Add(ttl)
+		c.lru.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.lru.PushFront(&lruEntry{
+		key:     key,
+		body:    body,
+		expires: time.Now().Add(ttl),
+	})
+	for c.lru.Len() > c.size {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// DiskCache is a Cache storing responses as files in a directory, so that they survive restarts
+type DiskCache struct {
+	dir string
+}
+
+type diskEntry struct {
+	Expires time.Time       `json:"expires"`
+	Body    json.RawMessage `json:"body"`
+}
+
+// NewDiskCache returns a DiskCache storing responses in dir, which is created if it does not exist
+func NewDiskCache(dir string) (*DiskCache, error) {
+
+	err := os.MkdirAll(dir, 0700)
+	if err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// Get returns the response cached under key, if present and not expired
+func (c *DiskCache) Get(key string) ([]byte, bool) {
+
+	data, err := ioutil.ReadFile(c.path(key))
+	if err != nil {
+		return nil, false
+	}
+	var entry diskEntry
+	err = json.Unmarshal(data, &entry)
+	if err != nil || time.Now().After(entry.Expires) {
+		os.Remove(c.path(key))
+		return nil, false
+	}
+	return entry.Body, true
+}
+
+// Set caches a response under key for ttl. Errors are ignored, since a failed write only results in a cache miss.
+func (c *DiskCache) Set(key string, body []byte, ttl time.Duration) {
+
+	data, err := json.Marshal(diskEntry{
+		Expires: time.Now().Add(ttl),
+		Body:    body,
+	})
+	if err != nil {
+		return
+	}
+	tmp, err := ioutil.TempFile(c.dir, "tmp")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(data)
+	tmp.Close()
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if os.Rename(tmp.Name(), c.path(key)) != nil {
+		os.Remove(tmp.Name())
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func countingGisqus(calls *int) Gisqus {
+
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			*calls++
+			return next.Do(ctx, req)
+		})
+	})
+	return g
+}
+
+func TestCache(t *testing.T) {
+
+	var calls int
+	g := countingGisqus(&calls)
+	g.SetCache(NewLRUCache(10), nil)
+
+	for i := 0; i < 3; i++ {
+		details, err := g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if details.Response.ID != "mapleleafshotstove" {
+			t.Fatal("Should inflate cached responses")
+		}
+	}
+	if calls != 1 {
+		t.Fatal("Should serve repeated calls from cache")
+	}
+	_, err := g.ForumDetails(testCtx, "otherforum", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 2 {
+		t.Fatal("Should key the cache on parameters")
+	}
+	_, err = g.PostList(testCtx, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.PostList(testCtx, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 4 {
+		t.Fatal("Should not cache endpoints without a ttl")
+	}
+
+	var raw *RawResponse
+	g.SetResponseHook(func(r *RawResponse) {
+		raw = r
+	})
+	_, err = g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if calls != 4 || raw.Header.Get("X-Ratelimit-Remaining") != "999" {
+		t.Fatal("Should serve the headers of cached responses")
+	}
+}
+
+func TestCacheKey(t *testing.T) {
+
+	a := &Request{Endpoint: "threads/set", Values: url.Values{"thread": {"1", "2"}, "forum": {"tmz"}}}
+	b := &Request{Endpoint: "threads/set", Values: url.Values{"forum": {"tmz"}, "thread": {"2", "1"}}}
+	if cacheKey(a) != cacheKey(b) {
+		t.Fatal("Should normalize the order of parameters and of their values")
+	}
+	if a.Values["thread"][0] != "1" {
+		t.Fatal("Should not modify the parameters of the call")
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+
+	c := NewLRUCache(2)
+	c.Set("a", []byte("a"), time.Hour)
+	c.Set("b", []byte("b"), time.Hour)
+	c.Get("a")
+	c.Set("c", []byte("c"), time.Hour)
+
+	if _, ok := c.Get("b"); ok {
+		t.Fatal("Should evict the least recently used entry")
+	}
+	if body, ok := c.Get("a"); !ok || string(body) != "a" {
+		t.Fatal("Should keep recently used entries")
+	}
+	c.Set("d", []byte("d"), -time.Second)
+	if _, ok := c.Get("d"); ok {
+		t.Fatal("Should expire entries")
+	}
+	c = NewLRUCache(0)
+	c.Set("a", []byte("a"), time.Hour)
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal("Should default the size of the cache")
+	}
+}
+
+func TestDiskCache(t *testing.T) {
+
+	c, err := NewDiskCache(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Set("forums/details?forum=a", []byte(`{"code":0}`), time.Hour)
+	if body, ok := c.Get("forums/details?forum=a"); !ok || string(body) != `{"code":0}` {
+		t.Fatal("Should retrieve cached entries")
+	}
+	if _, ok := c.Get("forums/details?forum=b"); ok {
+		t.Fatal("Should miss absent entries")
+	}
+	c.Set("forums/details?forum=c", []byte(`{"code":0}`), -time.Second)
+	if _, ok := c.Get("forums/details?forum=c"); ok {
+		t.Fatal("Should expire entries")
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+/*
+SetCoalescing enables or disables the coalescing of identical calls. When enabled, a call made while an identical one
+(same endpoint URL and parameters) is in flight does not reach Disqus: it waits for the in flight call and receives the
+same response, which is inflated separately for every caller. Streamed calls are never coalesced.
+*/
+func (g *Gisqus) SetCoalescing(enabled bool) {
+	if !enabled {
+		g.flights = nil
+		return
+	}
+	g.flights = &flightGroup{
+		calls: make(map[string]*flight),
+	}
+}
+
+type flight struct {
+	done chan struct{}
+	resp *Response
+	err  error
+}
+
+type flightGroup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+func (fg *flightGroup) middleware(next Doer) Doer {
+
+	return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+		if req.Stream {
+			// a stream can only be read by one caller
+			return next.Do(ctx, req)
+		}
+		key := req.URL + "?" + req.Values.Encode()
+
+		fg.mu.Lock()
+		for {
+			f, ok := fg.calls[key]
+			if !ok {
+				break
+			}
+			fg.mu.Unlock()
+			select {
+			case <-f.done:
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+			// a call cancelled by its own caller must not fail the others: they try again
+			if !errors.Is(f.err, context.Canceled) && !errors.Is(f.err, context.DeadlineExceeded) {
+				return f.resp, f.err
+			}
+			fg.mu.Lock()
+		}
+		f := &flight{done: make(chan struct{})}
+		fg.calls[key] = f
+		fg.mu.Unlock()
+
+		f.resp, f.err = next.Do(ctx, req)
+
+		fg.mu.Lock()
+		delete(fg.calls, key)
+		fg.mu.Unlock()
+		close(f.done)
+
+		return f.resp, f.err
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+func TestCoalescing(t *testing.T) {
+
+	var calls int32
+	release := make(chan struct{})
+
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			atomic.AddInt32(&calls, 1)
+			<-release
+			return next.Do(ctx, req)
+		})
+	})
+	g.SetCoalescing(true)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	joined := make(chan struct{}, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			details, err := g.UserDetails(newJoinContext(joined), "79849", url.Values{})
+			if err == nil && details.Response.ID != "79849" {
+				t.Error("Should inflate coalesced responses")
+			}
+			errs <- err
+		}()
+	}
+	// release the call only when every other caller has joined it
+	for i := 0; i < 9; i++ {
+		<-joined
+	}
+	close(release)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	if atomic.LoadInt32(&calls) != 1 {
+		t.Fatal("Should coalesce identical in flight calls")
+	}
+}
+
+/*
+joinContext signals on joined the first time its Done method is called. A caller coalesced with an in flight call waits
+on Done, while the caller making the call does not look at its context before reaching Disqus: the test middleware
+blocks it before that.
+*/
+type joinContext struct {
+	context.Context
+	once   sync.Once
+	joined chan<- struct{}
+}
+
+func newJoinContext(joined chan<- struct{}) context.Context {
+	return &joinContext{Context: testCtx, joined: joined}
+}
+
+func (c *joinContext) Done() <-chan struct{} {
+	c.once.Do(func() {
+		c.joined <- struct{}{}
+	})
+	return c.Context.Done()
+}
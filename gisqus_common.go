@@ -6,9 +6,11 @@ package gisqus
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"net/url"
 	"strconv"
 	"time"
 )
@@ -60,38 +62,125 @@ func call(ctx context.Context, url string) (string, error) {
 	return string(bytes), nil
 }
 
-func (g *Gisqus) callAndInflate(ctx context.Context, url string, v interface{}) error {
+func cloneValues(values url.Values) url.Values {
 
-	req, err := http.NewRequest("GET", url, nil)
+	clone := url.Values{}
+	for k, v := range values {
+		clone[k] = append([]string(nil), v...)
+	}
+	return clone
+}
+
+func (g *Gisqus) callAndInflate(ctx context.Context, endpoint, endpointURL string, values url.Values, v interface{}) error {
+
+	resp, err := g.send(ctx, endpoint, endpointURL, values, false)
 	if err != nil {
 		return err
 	}
+
+	return json.Unmarshal(resp.Body, v)
+}
+
+/*
+send validates the parameters of a call and makes it through the middleware chain. Middleware get a copy of values,
+so that the caller's are never modified.
+*/
+func (g *Gisqus) send(ctx context.Context, endpoint, endpointURL string, values url.Values, stream bool) (*Response, error) {
+
+	values = cloneValues(values)
+	err := validate(endpoint, values)
+	if err != nil {
+		return nil, err
+	}
+
+	req := &Request{
+		Endpoint: endpoint,
+		URL:      endpointURL,
+		Values:   values,
+		Attempt:  1,
+		Stream:   stream,
+	}
+	resp, err := g.doer().Do(ctx, req)
+
+	if resp != nil && g.responseHook != nil {
+		hook := g.responseHook
+		raw := &RawResponse{
+			URL:        req.encode("REDACTED"),
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Body:       resp.Body,
+		}
+		if resp.Stream != nil {
+			// the body is only known once the caller has read it
+			resp.Stream = &teeStream{ReadCloser: resp.Stream, done: func(body []byte) {
+				raw.Body = body
+				hook(raw)
+			}}
+		} else {
+			hook(raw)
+		}
+	}
+	return resp, err
+}
+
+func (g *Gisqus) do(ctx context.Context, r *Request) (*Response, error) {
+
+	key, wait := g.keys.pick()
+	if key == nil {
+		return nil, errors.New("Must provide one or more api keys")
+	}
+	if wait > 0 {
+		return nil, fmt.Errorf("%w: rate limit reset in %v", ErrQuotaExhausted, wait.Round(time.Second))
+	}
+	req, err := http.NewRequest("GET", r.encode(key.secret), nil)
+	if err != nil {
+		return nil, err
+	}
 	req = req.WithContext(ctx)
+	start := time.Now()
 	resp, err := http.DefaultClient.Do(req)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
-	defer resp.Body.Close()
 
 	drl, err := decodeRateLimits(resp.Header)
 	if err != nil {
-		return err
+		resp.Body.Close()
+		return nil, err
 	}
+	g.keys.update(key, drl)
+	g.limitsMu.Lock()
 	g.limits = drl
-
-	if resp.StatusCode != 200 {
-		disqusErrorBytes, _ := ioutil.ReadAll(resp.Body)
-		disqusError := string(disqusErrorBytes)
-		return fmt.Errorf("http response error %s, code: %d, Message: %s``", resp.Status, resp.StatusCode, disqusError)
+	g.limitsMu.Unlock()
+
+	if r.Stream && resp.StatusCode == 200 {
+		return &Response{
+			StatusCode: resp.StatusCode,
+			Header:     resp.Header,
+			Stream:     resp.Body,
+			Limits:     drl,
+			Latency:    time.Since(start),
+		}, nil
 	}
+	defer resp.Body.Close()
 
-	err = json.NewDecoder(resp.Body).Decode(v)
+	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return nil
+	response := &Response{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       body,
+		Limits:     drl,
+		Latency:    time.Since(start),
+	}
+	if resp.StatusCode != 200 {
+		return response, fmt.Errorf("http response error %s, code: %d, Message: %s``", resp.Status, resp.StatusCode, string(body))
+	}
+	return response, nil
 }
 
 func decodeRateLimits(header http.Header) (DisqusRateLimit, error) {
@@ -57,3 +57,38 @@ func readTestFile(fileName string) string {
 	return string(bytes)
 
 }
+
+func TestResponseHook(t *testing.T) {
+
+	var raw *RawResponse
+	g := NewGisqus("secret")
+	g.SetResponseHook(func(r *RawResponse) {
+		raw = r
+	})
+	_, err := g.PostDetails(testCtx, "3320987826", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw == nil {
+		t.Fatal("Should call the response hook")
+	}
+	if raw.StatusCode != 200 {
+		t.Fatal("Should be able to retrieve the status code")
+	}
+	if raw.Header.Get("X-Ratelimit-Remaining") != "999" {
+		t.Fatal("Should be able to retrieve the response headers")
+	}
+	if len(raw.Body) == 0 {
+		t.Fatal("Should be able to retrieve the response body")
+	}
+	rawURL, err := url.Parse(raw.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rawURL.Query().Get("api_secret") != "REDACTED" {
+		t.Fatal("Should redact the api secret")
+	}
+	if rawURL.Query().Get("post") != "3320987826" {
+		t.Fatal("Should be able to retrieve the request parameters")
+	}
+}
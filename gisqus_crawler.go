@@ -0,0 +1,170 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"net/url"
+	"sort"
+	"time"
+)
+
+/*
+TimeWindowCrawler crawls posts and threads created in a date range, which can be larger than what a single cursor walk
+returns reliably. The range is split into windows of Window; windows returning more than MaxItemsPerWindow items are
+halved until they fit, or until they are as small as MinWindow (in which case they are fetched entirely). MinWindow
+must be at least a second, the resolution of Disqus dates. Items are de-duplicated and emitted in chronological order.
+*/
+type TimeWindowCrawler struct {
+	gisqus            *Gisqus
+	Window            time.Duration
+	MinWindow         time.Duration
+	MaxItemsPerWindow int
+}
+
+// NewTimeWindowCrawler returns a TimeWindowCrawler with one day windows, shrinking down to one minute, of at most 1000 items
+func NewTimeWindowCrawler(g *Gisqus) *TimeWindowCrawler {
+	return &TimeWindowCrawler{
+		gisqus:            g,
+		Window:            24 * time.Hour,
+		MinWindow:         time.Minute,
+		MaxItemsPerWindow: 1000,
+	}
+}
+
+/*
+Posts crawls the posts created between from (included) and to (excluded), using PostList with the start and end
+parameters. values can be used to restrict the crawl, e.g. to a forum.
+*/
+func (c *TimeWindowCrawler) Posts(ctx context.Context, from, to time.Time, values url.Values) iter.Seq2[*Post, error] {
+
+	return crawlWindows(c, from, to, func(start, end time.Time, max int) ([]*Post, bool, error) {
+
+		values := cloneValues(values)
+		values.Set("start", ToDisqusTime(start.UTC()))
+		values.Set("end", ToDisqusTime(end.UTC()))
+		values.Set("order", string(OrderAsc))
+		values.Set("limit", "100")
+
+		var posts []*Post
+		for post, err := range c.gisqus.PostListAll(ctx, values) {
+			if err != nil {
+				return nil, false, err
+			}
+			if max > 0 && len(posts) == max {
+				return posts, true, nil
+			}
+			posts = append(posts, post)
+		}
+		return posts, false, nil
+	}, func(post *Post) (string, time.Time) {
+		return post.ID, post.CreatedAt
+	})
+}
+
+/*
+Threads crawls the threads created between from (included) and to (excluded), using ThreadList with the since
+parameter in ascending order (the thread list endpoint does not support start and end). values can be used to restrict
+the crawl, e.g. to a forum.
+*/
+func (c *TimeWindowCrawler) Threads(ctx context.Context, from, to time.Time, values url.Values) iter.Seq2[*Thread, error] {
+
+	return crawlWindows(c, from, to, func(start, end time.Time, max int) ([]*Thread, bool, error) {
+
+		values := cloneValues(values)
+		values.Set("since", ToDisqusTime(start.UTC()))
+		values.Set("order", string(OrderAsc))
+		values.Set("limit", "100")
+
+		var threads []*Thread
+		for thread, err := range c.gisqus.ThreadListAll(ctx, values) {
+			if err != nil {
+				return nil, false, err
+			}
+			if !thread.CreatedAt.Before(end) {
+				break
+			}
+			if max > 0 && len(threads) == max {
+				return threads, true, nil
+			}
+			threads = append(threads, thread)
+		}
+		return threads, false, nil
+	}, func(thread *Thread) (string, time.Time) {
+		return thread.ID, thread.CreatedAt
+	})
+}
+
+// crawlWindows calls fetch for every window. fetch returns the items of the window, and whether it stopped at max items
+// (max <= 0 means no maximum).
+func crawlWindows[T any](c *TimeWindowCrawler, from, to time.Time, fetch func(start, end time.Time, max int) ([]T, bool, error), key func(T) (string, time.Time)) iter.Seq2[T, error] {
+
+	return func(yield func(T, error) bool) {
+
+		if c.Window <= 0 || c.MaxItemsPerWindow <= 0 {
+			var zero T
+			yield(zero, errors.New("Window and MaxItemsPerWindow must be positive"))
+			return
+		}
+		if c.MinWindow < time.Second {
+			var zero T
+			yield(zero, errors.New("MinWindow must be at least a second"))
+			return
+		}
+		seen := make(map[string]bool)
+		window := c.Window
+
+		for start := from; start.Before(to); {
+			end := start.Add(window)
+			if end.After(to) {
+				end = to
+			}
+			items, truncated, err := fetch(start, end, c.MaxItemsPerWindow)
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if truncated {
+				if window/2 >= c.MinWindow {
+					window /= 2
+					continue
+				}
+				// the window cannot shrink any further, it is fetched entirely
+				items, _, err = fetch(start, end, 0)
+				if err != nil {
+					var zero T
+					yield(zero, err)
+					return
+				}
+			}
+
+			sort.SliceStable(items, func(i, j int) bool {
+				_, ti := key(items[i])
+				_, tj := key(items[j])
+				return ti.Before(tj)
+			})
+			for _, item := range items {
+				id, created := key(item)
+				if seen[id] || created.Before(start) || !created.Before(end) {
+					continue
+				}
+				seen[id] = true
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			start = end
+			if !truncated && window < c.Window {
+				window *= 2
+				if window > c.Window {
+					window = c.Window
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// minutePosts answers post list calls with a post per minute between start and end, both included
+func minutePosts(windows *int) Middleware {
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+			*windows++
+			start, _ := time.Parse(DisqusDateFormat, req.Values.Get("start"))
+			end, _ := time.Parse(DisqusDateFormat, req.Values.Get("end"))
+
+			plr := map[string]interface{}{"code": 0, "cursor": map[string]interface{}{"hasNext": false}}
+			var posts []map[string]interface{}
+			for t := start; !t.After(end); t = t.Add(time.Minute) {
+				posts = append(posts, map[string]interface{}{
+					"id":        t.Format("200601021504"),
+					"createdAt": ToDisqusTime(t),
+					"author":    map[string]interface{}{},
+				})
+			}
+			plr["response"] = posts
+			body, err := json.Marshal(plr)
+			if err != nil {
+				return nil, err
+			}
+			return &Response{StatusCode: 200, Body: body}, nil
+		})
+	}
+}
+
+// minuteThreads answers thread list calls with a thread per minute between since and last, both included
+func minuteThreads(last time.Time) Middleware {
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+			since, _ := time.Parse(DisqusDateFormat, req.Values.Get("since"))
+
+			tlr := map[string]interface{}{"code": 0, "cursor": map[string]interface{}{"hasNext": false}}
+			var threads []map[string]interface{}
+			for t := since; !t.After(last); t = t.Add(time.Minute) {
+				threads = append(threads, map[string]interface{}{
+					"id":        t.Format("200601021504"),
+					"createdAt": ToDisqusTime(t),
+				})
+			}
+			tlr["response"] = threads
+			body, err := json.Marshal(tlr)
+			if err != nil {
+				return nil, err
+			}
+			return &Response{StatusCode: 200, Body: body}, nil
+		})
+	}
+}
+
+func TestTimeWindowCrawler(t *testing.T) {
+
+	var windows int
+	g := NewGisqus("secret")
+	g.Use(minutePosts(&windows))
+
+	crawler := NewTimeWindowCrawler(&g)
+	crawler.MaxItemsPerWindow = 50
+
+	from := time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	var last time.Time
+	count := 0
+	for post, err := range crawler.Posts(testCtx, from, to, url.Values{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if post.CreatedAt.Before(last) {
+			t.Fatal("Should emit posts in chronological order")
+		}
+		if post.CreatedAt.Before(from) || !post.CreatedAt.Before(to) {
+			t.Fatal("Should only emit posts in range")
+		}
+		last = post.CreatedAt
+		count++
+	}
+	if count != 180 {
+		t.Fatal("Should emit every post exactly once", count)
+	}
+	if windows < 5 {
+		t.Fatal("Should shrink windows returning too many posts")
+	}
+
+	crawler.MinWindow = 2 * time.Hour
+	count = 0
+	for _, err := range crawler.Posts(testCtx, from, to, url.Values{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != 180 {
+		t.Fatal("Should fetch windows that cannot shrink entirely", count)
+	}
+}
+
+func TestTimeWindowCrawlerThreads(t *testing.T) {
+
+	from := time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+	to := from.Add(3 * time.Hour)
+
+	g := NewGisqus("secret")
+	g.Use(minuteThreads(to.Add(time.Hour)))
+
+	crawler := NewTimeWindowCrawler(&g)
+	crawler.MaxItemsPerWindow = 50
+
+	var last time.Time
+	count := 0
+	for thread, err := range crawler.Threads(testCtx, from, to, url.Values{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if thread.CreatedAt.Before(last) {
+			t.Fatal("Should emit threads in chronological order")
+		}
+		if thread.CreatedAt.Before(from) || !thread.CreatedAt.Before(to) {
+			t.Fatal("Should stop at the end of every window")
+		}
+		last = thread.CreatedAt
+		count++
+	}
+	if count != 180 {
+		t.Fatal("Should emit every thread exactly once", count)
+	}
+}
+
+func TestTimeWindowCrawlerSettings(t *testing.T) {
+
+	crawler := NewTimeWindowCrawler(&testGisqus)
+	crawler.MinWindow = time.Millisecond
+	var err error
+	for _, err = range crawler.Posts(testCtx, time.Now().Add(-time.Hour), time.Now(), url.Values{}) {
+		break
+	}
+	if err == nil {
+		t.Fatal("Should check MinWindow")
+	}
+}
@@ -41,12 +41,11 @@ func (gisqus *Gisqus) ForumMostActiveUsers(ctx context.Context, forumID string,
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	url := forumsUrls.MostActiveUsersURL + "?" + values.Encode()
 
 	var fulr ForumUserListResponse
-	err := gisqus.callAndInflate(ctx, url, &fulr)
+	err := gisqus.callAndInflate(ctx, "forums/listMostActiveUsers", forumsUrls.MostActiveUsersURL, values, &fulr)
 	if err != nil {
 		return nil, err
 	}
@@ -68,12 +67,11 @@ func (gisqus *Gisqus) ForumFollowers(ctx context.Context, forumID string, values
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	url := forumsUrls.ListFollowersURL + "?" + values.Encode()
 
 	var fulr ForumUserListResponse
-	err := gisqus.callAndInflate(ctx, url, &fulr)
+	err := gisqus.callAndInflate(ctx, "forums/listFollowers", forumsUrls.ListFollowersURL, values, &fulr)
 	if err != nil {
 		return nil, err
 	}
@@ -95,12 +93,11 @@ func (gisqus *Gisqus) ForumUsers(ctx context.Context, forumID string, values url
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	url := forumsUrls.ListUsersURL + "?" + values.Encode()
 
 	var fulr ForumUserListResponse
-	err := gisqus.callAndInflate(ctx, url, &fulr)
+	err := gisqus.callAndInflate(ctx, "forums/listUsers", forumsUrls.ListUsersURL, values, &fulr)
 	if err != nil {
 		return nil, err
 	}
@@ -119,11 +116,8 @@ ForumInteresting wraps https://disqus.com/api/docs/forums/interestingForums/ (ht
 */
 func (gisqus *Gisqus) ForumInteresting(ctx context.Context, values url.Values) (*InterestingForumsResponse, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := forumsUrls.InterestingForumsURL + "?" + values.Encode()
-
 	var ifr InterestingForumsResponse
-	err := gisqus.callAndInflate(ctx, url, &ifr)
+	err := gisqus.callAndInflate(ctx, "forums/interestingForums", forumsUrls.InterestingForumsURL, values, &ifr)
 	if err != nil {
 		return nil, err
 	}
@@ -146,13 +140,12 @@ func (gisqus *Gisqus) ForumDetails(ctx context.Context, forumID string, values u
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	url := forumsUrls.DetailsURL + "?" + values.Encode()
 
 	var fdr ForumDetailsResponse
 
-	err := gisqus.callAndInflate(ctx, url, &fdr)
+	err := gisqus.callAndInflate(ctx, "forums/details", forumsUrls.DetailsURL, values, &fdr)
 	if err != nil {
 		return nil, err
 	}
@@ -172,13 +165,12 @@ func (gisqus *Gisqus) ForumCategories(ctx context.Context, forumID string, value
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	values.Set("api_secret", gisqus.secret)
-	url := forumsUrls.CategoriesURL + "?" + values.Encode()
 
 	var clr CategoriesListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &clr)
+	err := gisqus.callAndInflate(ctx, "forums/listCategories", forumsUrls.CategoriesURL, values, &clr)
 	if err != nil {
 		return nil, err
 	}
@@ -193,13 +185,12 @@ func (gisqus *Gisqus) ForumThreads(ctx context.Context, forumID string, values u
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	values.Set("api_secret", gisqus.secret)
-	url := forumsUrls.ListThreadsURL + "?" + values.Encode()
 
 	var tlr ThreadListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &tlr)
+	err := gisqus.callAndInflate(ctx, "forums/listThreads", forumsUrls.ListThreadsURL, values, &tlr)
 
 	for _, thread := range tlr.Response {
 		thread.CreatedAt, err = fromDisqusTime(thread.DisqusTimeCreatedAt)
@@ -222,12 +213,11 @@ func (gisqus *Gisqus) ForumMostLikedUsers(ctx context.Context, forumID string, v
 	if forumID == "" {
 		return nil, errors.New("Must provide a forum id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("forum", forumID)
-	url := forumsUrls.MostLikedUsersURL + "?" + values.Encode()
 
 	var mlur MostLikedUsersResponse
-	err := gisqus.callAndInflate(ctx, url, &mlur)
+	err := gisqus.callAndInflate(ctx, "forums/listMostLikedUsers", forumsUrls.MostLikedUsersURL, values, &mlur)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,277 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"iter"
+	"net/url"
+)
+
+/*
+paginate walks the pages of a cursor endpoint. page is called with the parameters of every page, and must return its
+items and cursor. Iteration stops at the last page, at the first error (which is yielded) or when the consumer breaks
+out of the loop, in which case no further page is fetched.
+*/
+func paginate[T any](values url.Values, page func(url.Values) ([]T, *DisqusCursor, error)) iter.Seq2[T, error] {
+
+	return func(yield func(T, error) bool) {
+
+		values := cloneValues(values)
+		for {
+			items, cursor, err := page(cloneValues(values))
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+			if cursor == nil || !cursor.HasNext || cursor.Next == "" {
+				return
+			}
+			values.Set("cursor", cursor.Next)
+		}
+	}
+}
+
+/*
+ForumMostActiveUsersAll iterates over everything returned by ForumMostActiveUsers, fetching pages as needed.
+*/
+func (gisqus *Gisqus) ForumMostActiveUsersAll(ctx context.Context, forumID string, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.ForumMostActiveUsers(ctx, forumID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+ForumFollowersAll iterates over everything returned by ForumFollowers, fetching pages as needed.
+*/
+func (gisqus *Gisqus) ForumFollowersAll(ctx context.Context, forumID string, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.ForumFollowers(ctx, forumID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+ForumUsersAll iterates over everything returned by ForumUsers, fetching pages as needed.
+*/
+func (gisqus *Gisqus) ForumUsersAll(ctx context.Context, forumID string, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.ForumUsers(ctx, forumID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+ForumCategoriesAll iterates over everything returned by ForumCategories, fetching pages as needed.
+*/
+func (gisqus *Gisqus) ForumCategoriesAll(ctx context.Context, forumID string, values url.Values) iter.Seq2[*Category, error] {
+
+	return paginate(values, func(values url.Values) ([]*Category, *DisqusCursor, error) {
+		resp, err := gisqus.ForumCategories(ctx, forumID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+ForumThreadsAll iterates over everything returned by ForumThreads, fetching pages as needed. Threads are decoded as they
+are read (see ForumThreadsStream).
+*/
+func (gisqus *Gisqus) ForumThreadsAll(ctx context.Context, forumID string, values url.Values) iter.Seq2[*Thread, error] {
+
+	return paginateStream(values, func(values url.Values, fn func(*Thread) error) (*DisqusCursor, error) {
+		return gisqus.ForumThreadsStream(ctx, forumID, values, fn)
+	})
+}
+
+/*
+ForumMostLikedUsersAll iterates over everything returned by ForumMostLikedUsers, fetching pages as needed.
+*/
+func (gisqus *Gisqus) ForumMostLikedUsersAll(ctx context.Context, forumID string, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.ForumMostLikedUsers(ctx, forumID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+ThreadListAll iterates over everything returned by ThreadList, fetching pages as needed. Threads are decoded as they
+are read (see ThreadListStream).
+*/
+func (gisqus *Gisqus) ThreadListAll(ctx context.Context, values url.Values) iter.Seq2[*Thread, error] {
+
+	return paginateStream(values, func(values url.Values, fn func(*Thread) error) (*DisqusCursor, error) {
+		return gisqus.ThreadListStream(ctx, values, fn)
+	})
+}
+
+/*
+ThreadPostsAll iterates over everything returned by ThreadPosts, fetching pages as needed. Posts are decoded as they are
+read (see ThreadPostsStream).
+*/
+func (gisqus *Gisqus) ThreadPostsAll(ctx context.Context, threadID string, values url.Values) iter.Seq2[*Post, error] {
+
+	return paginateStream(values, func(values url.Values, fn func(*Post) error) (*DisqusCursor, error) {
+		return gisqus.ThreadPostsStream(ctx, threadID, values, fn)
+	})
+}
+
+/*
+PostListAll iterates over everything returned by PostList, fetching pages as needed. Posts are decoded as they are
+read (see PostListStream).
+*/
+func (gisqus *Gisqus) PostListAll(ctx context.Context, values url.Values) iter.Seq2[*Post, error] {
+
+	return paginateStream(values, func(values url.Values, fn func(*Post) error) (*DisqusCursor, error) {
+		return gisqus.PostListStream(ctx, values, fn)
+	})
+}
+
+/*
+UserActivitiesAll iterates over everything returned by UserActivities, fetching pages as needed.
+*/
+func (gisqus *Gisqus) UserActivitiesAll(ctx context.Context, userID string, values url.Values) iter.Seq2[*Post, error] {
+
+	return paginate(values, func(values url.Values) ([]*Post, *DisqusCursor, error) {
+		resp, err := gisqus.UserActivities(ctx, userID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Posts, resp.Cursor, nil
+	})
+}
+
+/*
+UserPostsAll iterates over everything returned by UserPosts, fetching pages as needed. Posts are decoded as they are
+read (see UserPostsStream).
+*/
+func (gisqus *Gisqus) UserPostsAll(ctx context.Context, userID string, values url.Values) iter.Seq2[*Post, error] {
+
+	return paginateStream(values, func(values url.Values, fn func(*Post) error) (*DisqusCursor, error) {
+		return gisqus.UserPostsStream(ctx, userID, values, fn)
+	})
+}
+
+/*
+UserActiveForumsAll iterates over everything returned by UserActiveForums, fetching pages as needed.
+*/
+func (gisqus *Gisqus) UserActiveForumsAll(ctx context.Context, userID string, values url.Values) iter.Seq2[*Forum, error] {
+
+	return paginate(values, func(values url.Values) ([]*Forum, *DisqusCursor, error) {
+		resp, err := gisqus.UserActiveForums(ctx, userID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+UserFollowersAll iterates over everything returned by UserFollowers, fetching pages as needed.
+*/
+func (gisqus *Gisqus) UserFollowersAll(ctx context.Context, userID string, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.UserFollowers(ctx, userID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+UserFollowingAll iterates over everything returned by UserFollowing, fetching pages as needed.
+*/
+func (gisqus *Gisqus) UserFollowingAll(ctx context.Context, userID string, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.UserFollowing(ctx, userID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+UserForumFollowingAll iterates over everything returned by UserForumFollowing, fetching pages as needed.
+*/
+func (gisqus *Gisqus) UserForumFollowingAll(ctx context.Context, userID string, values url.Values) iter.Seq2[*Forum, error] {
+
+	return paginate(values, func(values url.Values) ([]*Forum, *DisqusCursor, error) {
+		resp, err := gisqus.UserForumFollowing(ctx, userID, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		return resp.Response, resp.Cursor, nil
+	})
+}
+
+/*
+ForumInterestingAll iterates over the forums returned by ForumInteresting, in the order of their items, fetching pages
+as needed.
+*/
+func (gisqus *Gisqus) ForumInterestingAll(ctx context.Context, values url.Values) iter.Seq2[*Forum, error] {
+
+	return paginate(values, func(values url.Values) ([]*Forum, *DisqusCursor, error) {
+		resp, err := gisqus.ForumInteresting(ctx, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		var forums []*Forum
+		for _, item := range resp.Response.Items {
+			if forum, ok := resp.Response.Objects[item.ID]; ok {
+				forums = append(forums, forum)
+			}
+		}
+		return forums, resp.Cursor, nil
+	})
+}
+
+/*
+UserInterestingAll iterates over the users returned by UserInteresting, in the order of their items, fetching pages
+as needed.
+*/
+func (gisqus *Gisqus) UserInterestingAll(ctx context.Context, values url.Values) iter.Seq2[*User, error] {
+
+	return paginate(values, func(values url.Values) ([]*User, *DisqusCursor, error) {
+		resp, err := gisqus.UserInteresting(ctx, values)
+		if err != nil {
+			return nil, nil, err
+		}
+		var users []*User
+		for _, item := range resp.Response.Items {
+			if user, ok := resp.Response.Objects[item.ID]; ok {
+				users = append(users, user)
+			}
+		}
+		return users, resp.Cursor, nil
+	})
+}
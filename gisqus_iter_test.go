@@ -0,0 +1,104 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestIterators(t *testing.T) {
+
+	var cursors []string
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			cursors = append(cursors, req.Values.Get("cursor"))
+			return next.Do(ctx, req)
+		})
+	})
+
+	count := 0
+	for post, err := range g.ThreadPostsAll(testCtx, "5843656825", url.Values{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if post.ID == "" {
+			t.Fatal("Should yield posts")
+		}
+		count++
+		if count == 30 {
+			break
+		}
+	}
+	if len(cursors) != 2 || cursors[0] != "" || cursors[1] != "1495809366419136:0:0" {
+		t.Fatal("Should follow the cursor to the next page")
+	}
+
+	cursors = nil
+	for range g.ThreadPostsAll(testCtx, "5843656825", url.Values{}) {
+		break
+	}
+	if len(cursors) != 1 {
+		t.Fatal("Should stop fetching pages on break")
+	}
+
+	cursors = nil
+	count = 0
+	for _, err := range g.ForumMostActiveUsersAll(testCtx, "tmz", url.Values{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		count++
+	}
+	if count != 24 || len(cursors) != 1 {
+		t.Fatal("Should stop at the last page")
+	}
+
+	count = 0
+	for forum, err := range g.ForumInterestingAll(testCtx, url.Values{}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count == 0 && forum.ID == "" {
+			t.Fatal("Should yield interesting forums")
+		}
+		count++
+	}
+	if count == 0 {
+		t.Fatal("Should yield interesting forums")
+	}
+
+	for _, err := range g.UserPostsAll(testCtx, "", url.Values{}) {
+		if err == nil {
+			t.Fatal("Should yield errors")
+		}
+	}
+}
+
+func TestPaginateError(t *testing.T) {
+
+	pages := 0
+	seq := paginate(url.Values{}, func(values url.Values) ([]int, *DisqusCursor, error) {
+		pages++
+		if pages == 2 {
+			return nil, nil, errors.New("failed")
+		}
+		return []int{1, 2}, &DisqusCursor{HasNext: true, Next: "next"}, nil
+	})
+	var items []int
+	var err error
+	for item, e := range seq {
+		if e != nil {
+			err = e
+			break
+		}
+		items = append(items, item)
+	}
+	if len(items) != 2 || err == nil {
+		t.Fatal("Should yield items, then the error")
+	}
+}
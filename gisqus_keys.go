@@ -0,0 +1,126 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrQuotaExhausted is returned (wrapped) by calls made with several keys, while the quota of every key is exhausted
+var ErrQuotaExhausted = errors.New("quota exhausted")
+
+/*
+NewGisqusWithKeys returns a new instance of Gisqus that spreads calls over several API keys (e.g. the keys of several
+Disqus applications). Every call is made with the key having the most remaining quota, as reported by Disqus in the
+last call made with it. Keys whose quota has been exhausted are skipped until their rate limit is reset; when there are
+several keys and all of them are exhausted, calls fail with ErrQuotaExhausted without reaching Disqus. WaitForQuota
+blocks until a key is reset.
+*/
+func NewGisqusWithKeys(secrets ...string) Gisqus {
+
+	g := NewGisqus("")
+	g.keys = newKeyPool(secrets)
+	return g
+}
+
+type apiKey struct {
+	secret string
+	limits DisqusRateLimit
+	known  bool
+}
+
+func (k *apiKey) available(now time.Time) bool {
+	return !k.known || k.limits.RatelimitRemaining > 0 || !now.Before(k.limits.RatelimitReset)
+}
+
+type keyPool struct {
+	mu   sync.Mutex
+	keys []*apiKey
+	// next is where pick starts looking for keys never used
+	next int
+}
+
+func newKeyPool(secrets []string) *keyPool {
+
+	p := &keyPool{}
+	for _, secret := range secrets {
+		p.keys = append(p.keys, &apiKey{secret: secret})
+	}
+	return p
+}
+
+/*
+pick returns the available key with the most remaining quota. Keys never used count as having full quota, and are
+picked in turn, so that concurrent calls spread over them. If no key is available, the one reset first is returned; in
+pools of several keys, so is the time left before its reset, and calls are not made (see NewGisqusWithKeys).
+*/
+func (p *keyPool) pick() (*apiKey, time.Duration) {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.keys) == 0 {
+		return nil, 0
+	}
+	now := time.Now()
+	var best *apiKey
+	for i := range p.keys {
+		k := p.keys[(p.next+i)%len(p.keys)]
+		if !k.available(now) {
+			continue
+		}
+		if !k.known {
+			p.next = (p.next + i + 1) % len(p.keys)
+			return k, 0
+		}
+		if best == nil || k.limits.RatelimitRemaining > best.limits.RatelimitRemaining {
+			best = k
+		}
+	}
+	if best != nil {
+		return best, 0
+	}
+	for _, k := range p.keys {
+		if best == nil || k.limits.RatelimitReset.Before(best.limits.RatelimitReset) {
+			best = k
+		}
+	}
+	if len(p.keys) == 1 {
+		// a single key is always used, Disqus tells whether its quota is really exhausted
+		return best, 0
+	}
+	return best, best.limits.RatelimitReset.Sub(now)
+}
+
+func (p *keyPool) update(k *apiKey, drl DisqusRateLimit) {
+
+	if drl.RatelimitLimit == 0 {
+		return
+	}
+	p.mu.Lock()
+	k.limits = drl
+	k.known = true
+	p.mu.Unlock()
+}
+
+// wait returns how long to wait before a key is available
+func (p *keyPool) wait() time.Duration {
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	var first time.Time
+	for _, k := range p.keys {
+		if k.available(now) {
+			return 0
+		}
+		if first.IsZero() || k.limits.RatelimitReset.Before(first) {
+			first = k.limits.RatelimitReset
+		}
+	}
+	return first.Sub(now)
+}
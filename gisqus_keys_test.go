@@ -0,0 +1,120 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestKeyPool(t *testing.T) {
+
+	p := newKeyPool([]string{"a", "b", "c"})
+	reset := time.Now().Add(time.Hour)
+	pick := func() string {
+		k, _ := p.pick()
+		return k.secret
+	}
+
+	p.update(p.keys[0], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 10, RatelimitReset: reset})
+	p.update(p.keys[1], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 500, RatelimitReset: reset})
+	if pick() != "c" {
+		t.Fatal("Should prefer keys never used")
+	}
+	p.update(p.keys[2], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 0, RatelimitReset: reset})
+	if pick() != "b" {
+		t.Fatal("Should pick the key with the most remaining quota")
+	}
+	p.update(p.keys[1], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 0, RatelimitReset: reset.Add(time.Minute)})
+	if pick() != "a" {
+		t.Fatal("Should skip exhausted keys")
+	}
+	if p.wait() != 0 {
+		t.Fatal("Should not wait while a key is available")
+	}
+	p.update(p.keys[0], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 0, RatelimitReset: reset})
+	if p.wait() <= 0 {
+		t.Fatal("Should wait when all keys are exhausted")
+	}
+	if _, wait := p.pick(); wait <= 0 {
+		t.Fatal("Should tell how long to wait when all keys are exhausted")
+	}
+	p.update(p.keys[2], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 0, RatelimitReset: time.Now().Add(-time.Second)})
+	if pick() != "c" {
+		t.Fatal("Should use exhausted keys again once reset")
+	}
+}
+
+func TestGisqusWithKeys(t *testing.T) {
+
+	g := NewGisqusWithKeys("a", "b")
+	_, err := g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, k := range g.keys.keys {
+		if !k.known || k.limits.RatelimitRemaining != 999 {
+			t.Fatal("Should spread calls over all keys")
+		}
+	}
+
+	g = NewGisqusWithKeys()
+	_, err = g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+	if err == nil {
+		t.Fatal("Should check for missing api keys")
+	}
+}
+
+func TestQuotaExhausted(t *testing.T) {
+
+	g := NewGisqusWithKeys("a", "b")
+	g.SetCircuitBreaker(&CircuitBreakerSettings{FailureThreshold: 1, OpenTimeout: time.Hour})
+	reset := time.Now().Add(time.Hour)
+	for _, k := range g.keys.keys {
+		g.keys.update(k, DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 0, RatelimitReset: reset})
+	}
+	for i := 0; i < 2; i++ {
+		_, err := g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+		if !errors.Is(err, ErrQuotaExhausted) {
+			t.Fatal("Should not send calls when the quota of every key is exhausted")
+		}
+	}
+	if g.CircuitState("forums/details") != CircuitClosed {
+		t.Fatal("Should not count calls not sent as failures")
+	}
+	for _, k := range g.keys.keys {
+		if k.limits.RatelimitRemaining != 0 {
+			t.Fatal("Should not send calls when the quota of every key is exhausted")
+		}
+	}
+}
+
+func TestKeyPoolSpread(t *testing.T) {
+
+	p := newKeyPool([]string{"a", "b", "c"})
+	picked := make(map[string]bool)
+	for i := 0; i < 3; i++ {
+		k, _ := p.pick()
+		picked[k.secret] = true
+	}
+	if len(picked) != 3 {
+		t.Fatal("Should spread calls over keys never used")
+	}
+}
+
+func TestSingleKeyExhausted(t *testing.T) {
+
+	g := NewGisqus("secret")
+	g.keys.update(g.keys.keys[0], DisqusRateLimit{RatelimitLimit: 1000, RatelimitRemaining: 0, RatelimitReset: time.Now().Add(time.Hour)})
+	_, err := g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+	if err != nil {
+		t.Fatal("Should make calls with a single key even if it was reported as exhausted")
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+/*
+SetLogger makes Gisqus log every call made to Disqus on logger. Successful calls are logged at debug level, calls
+rejected by Disqus at warn level and calls that failed altogether (network errors, server errors) at error level.
+The api secret is never logged. A nil logger disables logging.
+*/
+func (g *Gisqus) SetLogger(logger *slog.Logger) {
+	g.logger = logger
+}
+
+func logRequests(logger *slog.Logger) Middleware {
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+			start := time.Now()
+			resp, err := next.Do(ctx, req)
+
+			attrs := []slog.Attr{
+				slog.String("endpoint", req.Endpoint),
+				slog.String("params", req.Values.Encode()),
+				slog.Duration("duration", time.Since(start)),
+			}
+			if resp != nil {
+				attrs = append(attrs,
+					slog.Int("status", resp.StatusCode),
+					slog.Int("ratelimit_remaining", resp.Limits.RatelimitRemaining),
+				)
+			}
+
+			switch {
+			case err == nil:
+				logger.LogAttrs(ctx, slog.LevelDebug, "disqus call", attrs...)
+			case resp != nil && resp.StatusCode < 500:
+				logger.LogAttrs(ctx, slog.LevelWarn, "disqus call rejected", append(attrs, slog.String("error", err.Error()))...)
+			default:
+				logger.LogAttrs(ctx, slog.LevelError, "disqus call failed", append(attrs, slog.String("error", err.Error()))...)
+			}
+			return resp, err
+		})
+	}
+}
@@ -0,0 +1,52 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestLogger(t *testing.T) {
+
+	var buf bytes.Buffer
+	g := NewGisqus("secret")
+	g.SetLogger(slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+	_, err := g.ForumDetails(testCtx, "mapleleafshotstove", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	line := buf.String()
+	if !strings.Contains(line, "level=DEBUG") {
+		t.Fatal("Should log successful calls at debug level")
+	}
+	if !strings.Contains(line, "endpoint=forums/details") {
+		t.Fatal("Should log the endpoint")
+	}
+	if !strings.Contains(line, "forum=mapleleafshotstove") {
+		t.Fatal("Should log the parameters")
+	}
+	if !strings.Contains(line, "status=200") || !strings.Contains(line, "ratelimit_remaining=999") {
+		t.Fatal("Should log status and rate limits")
+	}
+	if strings.Contains(line, "secret") {
+		t.Fatal("Should not log the api secret")
+	}
+
+	buf.Reset()
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	_, err = g.ForumDetails(ctx, "mapleleafshotstove", url.Values{})
+	if err == nil {
+		t.Fatal("Should fail on a cancelled context")
+	}
+	if !strings.Contains(buf.String(), "level=ERROR") {
+		t.Fatal("Should log failed calls at error level")
+	}
+}
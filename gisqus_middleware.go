@@ -0,0 +1,98 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Request models a call to a Disqus endpoint, as seen by middleware
+type Request struct {
+	// Endpoint is the name of the endpoint in Disqus' docs, e.g. "threads/details"
+	Endpoint string
+	// URL is the endpoint's URL, without query string
+	URL string
+	// Values are the parameters of the call. They never contain the api secret
+	Values url.Values
+	// Attempt is the number of times the request has been tried, starting at 1. Middleware retrying a request should
+	// increment it before calling the next Doer again
+	Attempt int
+	// Stream is true when the caller decodes the response while it is read, see Response.Stream
+	Stream bool
+}
+
+// Response models the answer of Disqus to a Request
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	// Body is the body of the response. It is nil for successful responses to streaming requests
+	Body []byte
+	// Stream is the body of successful responses to streaming requests, to be read and closed by the caller. Middleware
+	// must not read it
+	Stream io.ReadCloser
+	// Limits is the rate limit snapshot returned by Disqus with the response
+	Limits DisqusRateLimit
+	// Latency is the time spent waiting for Disqus
+	Latency time.Duration
+}
+
+// Doer executes a Request against Disqus. Implementations may return a non nil Response together with an error
+// (e.g. when Disqus answers with a status other than 200).
+type Doer interface {
+	Do(ctx context.Context, req *Request) (*Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface
+type DoerFunc func(ctx context.Context, req *Request) (*Response, error)
+
+// Do calls f(ctx, req)
+func (f DoerFunc) Do(ctx context.Context, req *Request) (*Response, error) {
+	return f(ctx, req)
+}
+
+// Middleware wraps a Doer into another Doer
+type Middleware func(next Doer) Doer
+
+/*
+Use appends a middleware to the chain that is applied to every call made to Disqus. Middleware registered first is
+outermost, i.e. it sees requests first and responses last. Responses served from cache (see SetCache) do not go
+through middleware, and neither do calls coalesced with an identical in flight call (see SetCoalescing) or rejected by
+the circuit breaker (see SetCircuitBreaker).
+*/
+func (g *Gisqus) Use(mw Middleware) {
+	g.middleware = append(g.middleware, mw)
+}
+
+func (g *Gisqus) doer() Doer {
+
+	var d Doer = DoerFunc(g.do)
+	if g.logger != nil {
+		d = logRequests(g.logger)(d)
+	}
+	for i := len(g.middleware) - 1; i >= 0; i-- {
+		d = g.middleware[i](d)
+	}
+	// the breaker sits inside coalescing, so that a call shared by several callers counts once
+	if g.breaker != nil {
+		d = g.breaker.middleware(d)
+	}
+	if g.flights != nil {
+		d = g.flights.middleware(d)
+	}
+	if g.cache != nil {
+		d = cacheResponses(g.cache, g.cacheTTLs)(d)
+	}
+	return d
+}
+
+func (r *Request) encode(secret string) string {
+
+	values := cloneValues(r.Values)
+	values.Set("api_secret", secret)
+	return r.URL + "?" + values.Encode()
+}
@@ -0,0 +1,73 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+
+	var calls []string
+	var seen *Request
+	var seenResp *Response
+
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			calls = append(calls, "outer")
+			seen = req
+			resp, err := next.Do(ctx, req)
+			seenResp = resp
+			return resp, err
+		})
+	})
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			calls = append(calls, "inner")
+			return next.Do(ctx, req)
+		})
+	})
+
+	_, err := g.ThreadDetails(testCtx, "5843656825", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(calls) != 2 || calls[0] != "outer" || calls[1] != "inner" {
+		t.Fatal("Should apply middleware in registration order")
+	}
+	if seen.Endpoint != "threads/details" {
+		t.Fatal("Should be able to retrieve the endpoint name")
+	}
+	if seen.Values.Get("thread") != "5843656825" {
+		t.Fatal("Should be able to retrieve the request parameters")
+	}
+	if seen.Values.Get("api_secret") != "" {
+		t.Fatal("Should not expose the api secret to middleware")
+	}
+	if seenResp.StatusCode != 200 {
+		t.Fatal("Should be able to retrieve the response status")
+	}
+	if seenResp.Limits.RatelimitRemaining != 999 {
+		t.Fatal("Should be able to retrieve the rate limits")
+	}
+}
+
+func TestMiddlewareShortCircuit(t *testing.T) {
+
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			return nil, errors.New("blocked")
+		})
+	})
+
+	_, err := g.ThreadDetails(testCtx, "5843656825", url.Values{})
+	if err == nil || err.Error() != "blocked" {
+		t.Fatal("Should return errors raised by middleware")
+	}
+}
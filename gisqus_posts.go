@@ -32,13 +32,12 @@ func (gisqus *Gisqus) PostDetails(ctx context.Context, postID string, values url
 	if postID == "" {
 		return nil, errors.New("Must use post parameter")
 	}
+	values = cloneValues(values)
 	values.Set("post", postID)
-	values.Set("api_secret", gisqus.secret)
-	url := postsUrls.PostDetailsURL + "?" + values.Encode()
 
 	var pdr PostDetailsResponse
 
-	err := gisqus.callAndInflate(ctx, url, &pdr)
+	err := gisqus.callAndInflate(ctx, "posts/details", postsUrls.PostDetailsURL, values, &pdr)
 	if err != nil {
 		return nil, err
 	}
@@ -58,12 +57,9 @@ PostList wraps https://disqus.com/api/docs/posts/list/ (https://disqus.com/api/3
 */
 func (gisqus *Gisqus) PostList(ctx context.Context, values url.Values) (*PostListResponse, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := postsUrls.PostListURL + "?" + values.Encode()
-
 	var plr PostListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &plr)
+	err := gisqus.callAndInflate(ctx, "posts/list", postsUrls.PostListURL, values, &plr)
 	if err != nil {
 		return nil, err
 	}
@@ -88,12 +84,9 @@ PostPopular wraps https://disqus.com/api/docs/posts/listPopular/ (https://disqus
 */
 func (gisqus *Gisqus) PostPopular(ctx context.Context, values url.Values) (*PostListResponseNoCursor, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := postsUrls.PostPopularURL + "?" + values.Encode()
-
 	var plr PostListResponseNoCursor
 
-	err := gisqus.callAndInflate(ctx, url, &plr)
+	err := gisqus.callAndInflate(ctx, "posts/listPopular", postsUrls.PostPopularURL, values, &plr)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,7 @@ package gisqus
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"testing"
 )
@@ -124,3 +125,20 @@ func TestPostPopular(t *testing.T) {
 	}
 
 }
+
+func TestPostValuesReuse(t *testing.T) {
+
+	values := url.Values{}
+	_, err := testGisqus.PostDetails(testCtx, "3320987826", values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values.Set("thread", "5843656825")
+	_, err = testGisqus.PostList(testCtx, values)
+	if err != nil {
+		t.Fatal("Should not leave parameters of a call in the caller's values - ", err)
+	}
+	if len(values) != 1 {
+		t.Fatal("Should not modify the caller's values")
+	}
+}
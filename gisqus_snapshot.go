@@ -0,0 +1,196 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/url"
+	"time"
+)
+
+/*
+ThreadSnapshot models the state of a thread and of its posts at a point in time. Snapshots can be saved as JSON with
+WriteJSON and read back with ReadThreadSnapshot, to be compared later with DiffThreadSnapshots.
+*/
+type ThreadSnapshot struct {
+	TakenAt time.Time     `json:"takenAt"`
+	Thread  *ThreadDetail `json:"thread"`
+	Posts   []*Post       `json:"posts"`
+}
+
+/*
+TakeThreadSnapshot fetches the details of a thread with ThreadDetails, and all of its posts with ThreadPosts. values are
+passed to ThreadPosts: e.g. include=deleted keeps deleted posts in the snapshot (which requires moderator rights).
+*/
+func (g *Gisqus) TakeThreadSnapshot(ctx context.Context, threadID string, values url.Values) (*ThreadSnapshot, error) {
+
+	takenAt := time.Now().UTC()
+	tdr, err := g.ThreadDetails(ctx, threadID, url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	values = cloneValues(values)
+	values.Set("limit", "100")
+	snapshot := &ThreadSnapshot{
+		TakenAt: takenAt,
+		Thread:  tdr.Response,
+	}
+	for post, err := range g.ThreadPostsAll(ctx, threadID, values) {
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Posts = append(snapshot.Posts, post)
+	}
+	return snapshot, nil
+}
+
+// WriteJSON writes the snapshot as JSON
+func (s *ThreadSnapshot) WriteJSON(w io.Writer) error {
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// ReadThreadSnapshot reads a snapshot written by WriteJSON
+func ReadThreadSnapshot(r io.Reader) (*ThreadSnapshot, error) {
+
+	var snapshot ThreadSnapshot
+	err := json.NewDecoder(r).Decode(&snapshot)
+	if err != nil {
+		return nil, err
+	}
+	if snapshot.Thread != nil {
+		err = inflateThread(&snapshot.Thread.Thread)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, post := range snapshot.Posts {
+		err = inflatePost(post)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &snapshot, nil
+}
+
+/*
+PostChange models the changes of a post between two snapshots. A post whose message changed is Edited; changes of
+IsApproved, IsSpam, IsFlagged and IsDeleted are moderation changes; deltas are the differences of vote counts.
+*/
+type PostChange struct {
+	ID             string `json:"id"`
+	Before         *Post  `json:"before"`
+	After          *Post  `json:"after"`
+	Edited         bool   `json:"edited"`
+	ApprovedChange bool   `json:"approvedChange"`
+	SpamChange     bool   `json:"spamChange"`
+	FlaggedChange  bool   `json:"flaggedChange"`
+	DeletedChange  bool   `json:"deletedChange"`
+	LikesDelta     int    `json:"likesDelta"`
+	DislikesDelta  int    `json:"dislikesDelta"`
+	PointsDelta    int    `json:"pointsDelta"`
+}
+
+// Moderated tells whether the moderation state of the post changed
+func (c *PostChange) Moderated() bool {
+	return c.ApprovedChange || c.SpamChange || c.FlaggedChange || c.DeletedChange
+}
+
+// Voted tells whether the vote counts of the post changed
+func (c *PostChange) Voted() bool {
+	return c.LikesDelta != 0 || c.DislikesDelta != 0 || c.PointsDelta != 0
+}
+
+/*
+ThreadDiff models the differences between two snapshots of a thread. Posts are listed in the order of the later
+snapshot (Added, Changed) or of the earlier one (Removed). Edited, Moderated and Voted are subsets of Changed.
+*/
+type ThreadDiff struct {
+	Thread        string        `json:"thread"`
+	From          time.Time     `json:"from"`
+	To            time.Time     `json:"to"`
+	Added         []*Post       `json:"added"`
+	Removed       []*Post       `json:"removed"`
+	Changed       []*PostChange `json:"changed"`
+	Edited        []*PostChange `json:"-"`
+	Moderated     []*PostChange `json:"-"`
+	Voted         []*PostChange `json:"-"`
+	LikesDelta    int           `json:"likesDelta"`
+	DislikesDelta int           `json:"dislikesDelta"`
+}
+
+// Empty tells whether the snapshots compared had the same posts, in the same state
+func (d *ThreadDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// DiffThreadSnapshots compares two snapshots of a thread
+func DiffThreadSnapshots(before, after *ThreadSnapshot) *ThreadDiff {
+
+	diff := &ThreadDiff{
+		From: before.TakenAt,
+		To:   after.TakenAt,
+	}
+	if after.Thread != nil {
+		diff.Thread = after.Thread.ID
+	}
+	if before.Thread != nil && after.Thread != nil {
+		diff.LikesDelta = after.Thread.Likes - before.Thread.Likes
+		diff.DislikesDelta = after.Thread.Dislikes - before.Thread.Dislikes
+	}
+
+	old := make(map[string]*Post, len(before.Posts))
+	for _, post := range before.Posts {
+		old[post.ID] = post
+	}
+	current := make(map[string]bool, len(after.Posts))
+	for _, post := range after.Posts {
+		if current[post.ID] {
+			continue
+		}
+		current[post.ID] = true
+		prev, ok := old[post.ID]
+		if !ok {
+			diff.Added = append(diff.Added, post)
+			continue
+		}
+		change := &PostChange{
+			ID:             post.ID,
+			Before:         prev,
+			After:          post,
+			Edited:         post.Message != prev.Message,
+			ApprovedChange: post.IsApproved != prev.IsApproved,
+			SpamChange:     post.IsSpam != prev.IsSpam,
+			FlaggedChange:  post.IsFlagged != prev.IsFlagged,
+			DeletedChange:  post.IsDeleted != prev.IsDeleted,
+			LikesDelta:     post.Likes - prev.Likes,
+			DislikesDelta:  post.Dislikes - prev.Dislikes,
+			PointsDelta:    post.Points - prev.Points,
+		}
+		if !change.Edited && !change.Moderated() && !change.Voted() {
+			continue
+		}
+		diff.Changed = append(diff.Changed, change)
+		if change.Edited {
+			diff.Edited = append(diff.Edited, change)
+		}
+		if change.Moderated() {
+			diff.Moderated = append(diff.Moderated, change)
+		}
+		if change.Voted() {
+			diff.Voted = append(diff.Voted, change)
+		}
+	}
+	for _, post := range before.Posts {
+		if !current[post.ID] {
+			diff.Removed = append(diff.Removed, post)
+		}
+	}
+	return diff
+}
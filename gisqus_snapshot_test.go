@@ -0,0 +1,103 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"bytes"
+	"context"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestTakeThreadSnapshot(t *testing.T) {
+
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			body := `{"code":0,"response":{"id":"1","likes":3,"createdAt":"2017-05-23T10:00:00"}}`
+			if req.Endpoint == "threads/listPosts" {
+				body = `{"code":0,"cursor":{"hasNext":false},"response":[
+					{"id":"10","message":"first","createdAt":"2017-05-23T10:10:00","author":{"joinedAt":"2010-01-01T00:00:00"}},
+					{"id":"11","message":"second","createdAt":"2017-05-23T10:20:00"}]}`
+			}
+			return &Response{StatusCode: 200, Body: []byte(body)}, nil
+		})
+	})
+
+	snapshot, err := g.TakeThreadSnapshot(testCtx, "1", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Thread.ID != "1" || len(snapshot.Posts) != 2 || snapshot.TakenAt.IsZero() {
+		t.Fatal("Should snapshot the thread and its posts")
+	}
+
+	var buf bytes.Buffer
+	err = snapshot.WriteJSON(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	read, err := ReadThreadSnapshot(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !read.TakenAt.Equal(snapshot.TakenAt) || read.Thread.Likes != 3 || !read.Thread.CreatedAt.Equal(snapshot.Thread.CreatedAt) {
+		t.Fatal("Should read back the thread")
+	}
+	if len(read.Posts) != 2 || !read.Posts[0].CreatedAt.Equal(time.Date(2017, 5, 23, 10, 10, 0, 0, time.UTC)) || read.Posts[0].Author.JoinedAt.Year() != 2010 {
+		t.Fatal("Should read back the posts")
+	}
+	if !DiffThreadSnapshots(snapshot, read).Empty() {
+		t.Fatal("Should find no differences between a snapshot and its copy")
+	}
+
+	_, err = g.TakeThreadSnapshot(testCtx, "", url.Values{})
+	if err == nil {
+		t.Fatal("Should check for an empty thread id")
+	}
+}
+
+func TestDiffThreadSnapshots(t *testing.T) {
+
+	t0 := time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+	before := &ThreadSnapshot{
+		TakenAt: t0,
+		Thread:  &ThreadDetail{Thread: Thread{ID: "1", Likes: 5}},
+		Posts:   []*Post{treePost("10", 0, 0, 0), treePost("11", 0, 0, 0), treePost("12", 0, 0, 0), treePost("13", 0, 0, 0)},
+	}
+	after := &ThreadSnapshot{
+		TakenAt: t0.Add(time.Hour),
+		Thread:  &ThreadDetail{Thread: Thread{ID: "1", Likes: 7, Dislikes: 1}},
+		Posts:   []*Post{treePost("10", 0, 0, 0), treePost("11", 0, 0, 0), treePost("13", 0, 0, 0), treePost("14", 0, 0, 0)},
+	}
+	before.Posts[0].Message, after.Posts[0].Message = "first", "first, edited"
+	before.Posts[0].Likes, after.Posts[0].Likes = 1, 1
+	before.Posts[1].IsApproved = true
+	after.Posts[1].Likes = 4
+	after.Posts[1].IsSpam = true
+
+	diff := DiffThreadSnapshots(before, after)
+	if diff.Thread != "1" || !diff.From.Equal(t0) || diff.LikesDelta != 2 || diff.DislikesDelta != 1 {
+		t.Fatal("Should compare the threads")
+	}
+	if len(diff.Added) != 1 || diff.Added[0].ID != "14" {
+		t.Fatal("Should find added posts")
+	}
+	if len(diff.Removed) != 1 || diff.Removed[0].ID != "12" {
+		t.Fatal("Should find removed posts")
+	}
+	if len(diff.Changed) != 2 || len(diff.Edited) != 1 || diff.Edited[0].ID != "10" || diff.Edited[0].Before.Message != "first" {
+		t.Fatal("Should find edited posts")
+	}
+	if len(diff.Moderated) != 1 || !diff.Moderated[0].SpamChange || !diff.Moderated[0].ApprovedChange || diff.Moderated[0].FlaggedChange {
+		t.Fatal("Should find moderation changes")
+	}
+	if len(diff.Voted) != 1 || diff.Voted[0].ID != "11" || diff.Voted[0].LikesDelta != 4 {
+		t.Fatal("Should find vote deltas")
+	}
+	if diff.Empty() {
+		t.Fatal("Should not be empty")
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net/url"
+	"sync"
+)
+
+/*
+PostListStream is like PostList, but instead of inflating the whole page in memory it calls fn with every post as soon
+as it is decoded. It returns the cursor of the page, which Disqus sends after the posts. If fn returns an error,
+decoding stops and the error is returned. If a response hook is set (see SetResponseHook), the body is also buffered
+for the hook, and memory is no longer bounded.
+*/
+func (gisqus *Gisqus) PostListStream(ctx context.Context, values url.Values, fn func(*Post) error) (*DisqusCursor, error) {
+
+	return streamList(ctx, gisqus, "posts/list", postsUrls.PostListURL, values, inflatePost, fn)
+}
+
+/*
+ThreadPostsStream is like ThreadPosts, but it calls fn with every post as soon as it is decoded (see PostListStream).
+*/
+func (gisqus *Gisqus) ThreadPostsStream(ctx context.Context, threadID string, values url.Values, fn func(*Post) error) (*DisqusCursor, error) {
+
+	if threadID == "" {
+		return nil, errors.New("Must provide a thread id")
+	}
+	values = cloneValues(values)
+	values.Set("thread", threadID)
+	return streamList(ctx, gisqus, "threads/listPosts", threadsUrls.ThreadPostsURL, values, inflatePost, fn)
+}
+
+/*
+UserPostsStream is like UserPosts, but it calls fn with every post as soon as it is decoded (see PostListStream).
+*/
+func (gisqus *Gisqus) UserPostsStream(ctx context.Context, userID string, values url.Values, fn func(*Post) error) (*DisqusCursor, error) {
+
+	if userID == "" {
+		return nil, errors.New("Must provide a user id")
+	}
+	values = cloneValues(values)
+	values.Set("user", userID)
+	return streamList(ctx, gisqus, "users/listPosts", usersUrls.PostListURL, values, inflatePost, fn)
+}
+
+/*
+ThreadListStream is like ThreadList, but it calls fn with every thread as soon as it is decoded (see PostListStream).
+*/
+func (gisqus *Gisqus) ThreadListStream(ctx context.Context, values url.Values, fn func(*Thread) error) (*DisqusCursor, error) {
+
+	return streamList(ctx, gisqus, "threads/list", threadsUrls.ThreadListURL, values, inflateThread, fn)
+}
+
+/*
+ForumThreadsStream is like ForumThreads, but it calls fn with every thread as soon as it is decoded (see PostListStream).
+*/
+func (gisqus *Gisqus) ForumThreadsStream(ctx context.Context, forumID string, values url.Values, fn func(*Thread) error) (*DisqusCursor, error) {
+
+	if forumID == "" {
+		return nil, errors.New("Must provide a forum id")
+	}
+	values = cloneValues(values)
+	values.Set("forum", forumID)
+	return streamList(ctx, gisqus, "forums/listThreads", forumsUrls.ListThreadsURL, values, inflateThread, fn)
+}
+
+func inflatePost(post *Post) error {
+
+	var err error
+	post.CreatedAt, err = fromDisqusTime(post.DisqusTimeCreatedAt)
+	if err != nil {
+		return err
+	}
+	if post.Author != nil {
+		post.Author.JoinedAt, err = fromDisqusTime(post.Author.DisqusTimeJoinedAt)
+	}
+	return err
+}
+
+func inflateThread(thread *Thread) error {
+
+	var err error
+	thread.CreatedAt, err = fromDisqusTime(thread.DisqusTimeCreatedAt)
+	return err
+}
+
+// teeStream keeps a copy of what is read from a stream, and hands it to done when the stream is exhausted or closed
+type teeStream struct {
+	io.ReadCloser
+	buf  bytes.Buffer
+	done func([]byte)
+	once sync.Once
+}
+
+func (s *teeStream) Read(p []byte) (int, error) {
+
+	n, err := s.ReadCloser.Read(p)
+	s.buf.Write(p[:n])
+	if err == io.EOF {
+		s.finish()
+	}
+	return n, err
+}
+
+func (s *teeStream) Close() error {
+
+	s.finish()
+	return s.ReadCloser.Close()
+}
+
+func (s *teeStream) finish() {
+	s.once.Do(func() {
+		s.done(s.buf.Bytes())
+	})
+}
+
+// streamList makes a streaming call to a list endpoint, and decodes the elements of its response one at a time
+func streamList[T any](ctx context.Context, g *Gisqus, endpoint, endpointURL string, values url.Values, inflate func(T) error, fn func(T) error) (*DisqusCursor, error) {
+
+	resp, err := g.send(ctx, endpoint, endpointURL, values, true)
+	if err != nil {
+		return nil, err
+	}
+	var body io.Reader
+	if resp.Stream != nil {
+		defer resp.Stream.Close()
+		body = resp.Stream
+	} else {
+		// e.g. served from cache
+		body = bytes.NewReader(resp.Body)
+	}
+
+	dec := json.NewDecoder(body)
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var cursor *DisqusCursor
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch token {
+		case "cursor":
+			err = dec.Decode(&cursor)
+		case "response":
+			err = decodeElements(dec, inflate, fn)
+		default:
+			var skip json.RawMessage
+			err = dec.Decode(&skip)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return cursor, expectDelim(dec, '}')
+}
+
+func decodeElements[T any](dec *json.Decoder, inflate func(T) error, fn func(T) error) error {
+
+	if err := expectDelim(dec, '['); err != nil {
+		return err
+	}
+	for dec.More() {
+		var element T
+		err := dec.Decode(&element)
+		if err != nil {
+			return err
+		}
+		err = inflate(element)
+		if err != nil {
+			return err
+		}
+		err = fn(element)
+		if err != nil {
+			return err
+		}
+	}
+	return expectDelim(dec, ']')
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+
+	token, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("malformed Disqus response: expected %s, got %v", delim, token)
+	}
+	return nil
+}
+
+var errStopStream = errors.New("stream stopped by consumer")
+
+/*
+paginateStream is like paginate, but page decodes items one at a time and hands them to its second argument.
+Breaking out of the loop stops reading the current page.
+*/
+func paginateStream[T any](values url.Values, page func(url.Values, func(T) error) (*DisqusCursor, error)) iter.Seq2[T, error] {
+
+	return func(yield func(T, error) bool) {
+
+		values := cloneValues(values)
+		for {
+			cursor, err := page(cloneValues(values), func(item T) error {
+				if !yield(item, nil) {
+					return errStopStream
+				}
+				return nil
+			})
+			if err == errStopStream {
+				return
+			}
+			if err != nil {
+				var zero T
+				yield(zero, err)
+				return
+			}
+			if cursor == nil || !cursor.HasNext || cursor.Next == "" {
+				return
+			}
+			values.Set("cursor", cursor.Next)
+		}
+	}
+}
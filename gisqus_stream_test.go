@@ -0,0 +1,115 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func TestPostListStream(t *testing.T) {
+
+	plr, err := testGisqus.PostList(testCtx, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var posts []*Post
+	cursor, err := testGisqus.PostListStream(testCtx, url.Values{}, func(post *Post) error {
+		posts = append(posts, post)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(posts) != len(plr.Response) {
+		t.Fatal("Should decode every post")
+	}
+	for i, post := range posts {
+		if post.ID != plr.Response[i].ID || !post.CreatedAt.Equal(plr.Response[i].CreatedAt) || !post.Author.JoinedAt.Equal(plr.Response[i].Author.JoinedAt) {
+			t.Fatal("Should decode posts like PostList")
+		}
+	}
+	if cursor == nil || cursor.Next != plr.Cursor.Next {
+		t.Fatal("Should return the cursor")
+	}
+
+	stop := errors.New("stop")
+	count := 0
+	_, err = testGisqus.PostListStream(testCtx, url.Values{}, func(post *Post) error {
+		count++
+		return stop
+	})
+	if err != stop || count != 1 {
+		t.Fatal("Should stop decoding when fn fails")
+	}
+}
+
+func TestThreadListStreamFromCache(t *testing.T) {
+
+	var calls int
+	g := countingGisqus(&calls)
+	g.SetCache(NewLRUCache(10), map[string]time.Duration{"threads/list": time.Hour})
+
+	_, err := g.ThreadList(testCtx, url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	count := 0
+	cursor, err := g.ThreadListStream(testCtx, url.Values{}, func(thread *Thread) error {
+		count++
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 25 || cursor == nil {
+		t.Fatal("Should decode cached threads")
+	}
+	if calls != 1 {
+		t.Fatal("Should serve streams from cache")
+	}
+}
+
+func TestStreamMalformed(t *testing.T) {
+
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			return &Response{StatusCode: 200, Body: []byte(`{"code":0,"response":{}}`)}, nil
+		})
+	})
+	_, err := g.PostListStream(testCtx, url.Values{}, func(post *Post) error {
+		return nil
+	})
+	if err == nil {
+		t.Fatal("Should reject responses that are not lists")
+	}
+}
+
+func TestStreamResponseHook(t *testing.T) {
+
+	var raw *RawResponse
+	g := NewGisqus("secret")
+	g.SetResponseHook(func(r *RawResponse) {
+		raw = r
+	})
+	_, err := g.PostListStream(testCtx, url.Values{}, func(post *Post) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if raw == nil {
+		t.Fatal("Should call the response hook for streamed calls")
+	}
+	var plr PostListResponse
+	if err := json.Unmarshal(raw.Body, &plr); err != nil || len(plr.Response) == 0 {
+		t.Fatal("Should pass the streamed body to the response hook")
+	}
+}
@@ -4,8 +4,13 @@
 package gisqus
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/url"
 	"os"
+	"strconv"
+	"sync"
 	"testing"
 )
 
@@ -107,6 +112,75 @@ func TestThreadSet(t *testing.T) {
 	}
 }
 
+func TestThreadSetAll(t *testing.T) {
+
+	var mu sync.Mutex
+	var chunkSizes []int
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			mu.Lock()
+			chunkSizes = append(chunkSizes, len(req.Values["thread"]))
+			mu.Unlock()
+			return next.Do(ctx, req)
+		})
+	})
+
+	_, err := g.ThreadSetAll(testCtx, nil, url.Values{}, 2)
+	if err == nil {
+		t.Fatal("Should check for an empty thread id")
+	}
+
+	ids := []string{"5850192558"}
+	for i := 0; i < 2*ThreadSetMaxIDs; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	ids = append(ids, "5903840168")
+
+	result, err := g.ThreadSetAll(testCtx, ids, url.Values{}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(chunkSizes) != 3 {
+		t.Fatal("Should split thread ids in chunks")
+	}
+	for _, size := range chunkSizes {
+		if size > ThreadSetMaxIDs {
+			t.Fatal("Should not exceed the maximum number of ids per call")
+		}
+	}
+	if len(result.Threads) != 2 || result.Threads[0].ID != "5850192558" || result.Threads[1].ID != "5903840168" {
+		t.Fatal("Should return threads in input order")
+	}
+	if len(result.Missing) != 2*ThreadSetMaxIDs || result.Missing[0] != "0" {
+		t.Fatal("Should report missing thread ids")
+	}
+}
+
+func TestThreadSetAllFailure(t *testing.T) {
+
+	calls := 0
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			calls++
+			return nil, errors.New("connection refused")
+		})
+	})
+
+	var ids []string
+	for i := 0; i < 5*ThreadSetMaxIDs; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	_, err := g.ThreadSetAll(testCtx, ids, url.Values{}, 1)
+	if err == nil || err.Error() != "connection refused" {
+		t.Fatal("Should return the first error")
+	}
+	if calls != 1 {
+		t.Fatal("Should not request more chunks once one failed")
+	}
+}
+
 func TestThreadList(t *testing.T) {
 
 	threads, err := testGisqus.ThreadList(testCtx, testValues)
@@ -7,6 +7,7 @@ import (
 	"context"
 	"errors"
 	"net/url"
+	"sync"
 	"time"
 )
 
@@ -42,13 +43,12 @@ func (gisqus *Gisqus) ThreadUsersVoted(ctx context.Context, threadD string, valu
 	if threadD == "" {
 		return nil, errors.New("Must provide a thread id")
 	}
+	values = cloneValues(values)
 	values.Set("thread", threadD)
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadUsersVotedURL + "?" + values.Encode()
 
 	var uvr UsersVotedResponse
 
-	err := gisqus.callAndInflate(ctx, url, &uvr)
+	err := gisqus.callAndInflate(ctx, "threads/listUsersVotedThread", threadsUrls.ThreadUsersVotedURL, values, &uvr)
 	if err != nil {
 		return nil, err
 	}
@@ -68,12 +68,9 @@ It does not support the "related" argument (related fields can be gotten with ca
 */
 func (gisqus *Gisqus) ThreadList(ctx context.Context, values url.Values) (*ThreadListResponse, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadListURL + "?" + values.Encode()
-
 	var tlr ThreadListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &tlr)
+	err := gisqus.callAndInflate(ctx, "threads/list", threadsUrls.ThreadListURL, values, &tlr)
 	if err != nil {
 		return nil, err
 	}
@@ -93,12 +90,9 @@ It does not support the "related" argument (related fields can be gotten with ca
 */
 func (gisqus *Gisqus) ThreadTrending(ctx context.Context, values url.Values) (*ThreadTrendingResponse, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadTrendingURL + "?" + values.Encode()
-
 	var tlr ThreadTrendingResponse
 
-	err := gisqus.callAndInflate(ctx, url, &tlr)
+	err := gisqus.callAndInflate(ctx, "trends/listThreads", threadsUrls.ThreadTrendingURL, values, &tlr)
 	if err != nil {
 		return nil, err
 	}
@@ -132,15 +126,14 @@ func (gisqus *Gisqus) ThreadSet(ctx context.Context, threadsIDs []string, values
 	if threadsIDs == nil || len(threadsIDs) == 0 {
 		return nil, errors.New("Must provide one or more thread ids")
 	}
+	values = cloneValues(values)
 	for _, thread := range threadsIDs {
 		values.Add("thread", thread)
 	}
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadSetURL + "?" + values.Encode()
 
 	var tlr ThreadListResponseNoCursor
 
-	err := gisqus.callAndInflate(ctx, url, &tlr)
+	err := gisqus.callAndInflate(ctx, "threads/set", threadsUrls.ThreadSetURL, values, &tlr)
 	if err != nil {
 		return nil, err
 	}
@@ -154,6 +147,88 @@ func (gisqus *Gisqus) ThreadSet(ctx context.Context, threadsIDs []string, values
 
 }
 
+// ThreadSetMaxIDs is the maximum number of threads accepted by Disqus in a single call to the thread set endpoint
+const ThreadSetMaxIDs = 100
+
+/*
+ThreadSetAll is like ThreadSet, but it accepts any number of thread ids. Ids are split into chunks of at most
+ThreadSetMaxIDs, which are requested with at most concurrency calls in flight. Threads are returned in the order of
+threadsIDs, and ids not returned by Disqus are reported in Missing. The first failing call makes ThreadSetAll fail with
+its error, and no further chunk is requested.
+*/
+func (gisqus *Gisqus) ThreadSetAll(ctx context.Context, threadsIDs []string, values url.Values, concurrency int) (*ThreadSetResult, error) {
+
+	if len(threadsIDs) == 0 {
+		return nil, errors.New("Must provide one or more thread ids")
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var chunks [][]string
+	for start := 0; start < len(threadsIDs); start += ThreadSetMaxIDs {
+		end := start + ThreadSetMaxIDs
+		if end > len(threadsIDs) {
+			end = len(threadsIDs)
+		}
+		chunks = append(chunks, threadsIDs[start:end])
+	}
+
+	var mu sync.Mutex
+	var firstErr error
+	found := make(map[string]*Thread, len(threadsIDs))
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+	for _, chunk := range chunks {
+		sem <- struct{}{}
+		if ctx.Err() != nil {
+			// a chunk failed (or the caller gave up): no more chunks are requested
+			<-sem
+			break
+		}
+		wg.Add(1)
+		go func(chunk []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			tlr, err := gisqus.ThreadSet(ctx, chunk, values)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+					cancel()
+				}
+				return
+			}
+			for _, thread := range tlr.Response {
+				found[thread.ID] = thread
+			}
+		}(chunk)
+	}
+	wg.Wait()
+
+	if firstErr == nil {
+		firstErr = ctx.Err()
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	result := ThreadSetResult{}
+	for _, id := range threadsIDs {
+		if thread, ok := found[id]; ok {
+			result.Threads = append(result.Threads, thread)
+		} else {
+			result.Missing = append(result.Missing, id)
+		}
+	}
+	return &result, nil
+}
+
 /*
 ThreadDetails wraps https://disqus.com/api/docs/threads/details/ (https://disqus.com/api/3.0/threads/details.json)
 It does not support the "related" argument (related fields can be gotten with calls to their respective APIS)
@@ -163,12 +238,11 @@ func (gisqus *Gisqus) ThreadDetails(ctx context.Context, threadID string, values
 	if threadID == "" {
 		return nil, errors.New("Must provide thread id")
 	}
+	values = cloneValues(values)
 	values.Set("thread", threadID)
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadDetailURL + "?" + values.Encode()
 
 	var tdr ThreadDetailResponse
-	err := gisqus.callAndInflate(ctx, url, &tdr)
+	err := gisqus.callAndInflate(ctx, "threads/details", threadsUrls.ThreadDetailURL, values, &tdr)
 	if err != nil {
 		return nil, err
 	}
@@ -190,13 +264,12 @@ func (gisqus *Gisqus) ThreadPosts(ctx context.Context, threadID string, values u
 	if threadID == "" {
 		return nil, errors.New("Must provide a thread id")
 	}
+	values = cloneValues(values)
 	values.Set("thread", threadID)
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadPostsURL + "?" + values.Encode()
 
 	var plr PostListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &plr)
+	err := gisqus.callAndInflate(ctx, "threads/listPosts", threadsUrls.ThreadPostsURL, values, &plr)
 	if err != nil {
 		return nil, err
 	}
@@ -222,12 +295,9 @@ It does not support the "related" argument (related fields can be gotten with ca
 */
 func (gisqus *Gisqus) ThreadHot(ctx context.Context, values url.Values) (*ThreadListResponseNoCursor, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadHotURL + "?" + values.Encode()
-
 	var tlr ThreadListResponseNoCursor
 
-	err := gisqus.callAndInflate(ctx, url, &tlr)
+	err := gisqus.callAndInflate(ctx, "threads/listHot", threadsUrls.ThreadHotURL, values, &tlr)
 	if err != nil {
 		return nil, err
 	}
@@ -246,12 +316,9 @@ It does not support the "related" argument (related fields can be gotten with ca
 */
 func (gisqus *Gisqus) ThreadPopular(ctx context.Context, values url.Values) (*ThreadListResponseNoCursor, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := threadsUrls.ThreadPopularURL + "?" + values.Encode()
-
 	var tlr ThreadListResponseNoCursor
 
-	err := gisqus.callAndInflate(ctx, url, &tlr)
+	err := gisqus.callAndInflate(ctx, "threads/listPopular", threadsUrls.ThreadPopularURL, values, &tlr)
 	if err != nil {
 		return nil, err
 	}
@@ -270,6 +337,12 @@ type UsersVotedResponse struct {
 	Response []*User `json:"response"`
 }
 
+// ThreadSetResult models the result of ThreadSetAll
+type ThreadSetResult struct {
+	Threads []*Thread
+	Missing []string
+}
+
 // ThreadDetailResponse models the response of the thread details endpoint.
 type ThreadDetailResponse struct {
 	ResponseStub
@@ -0,0 +1,192 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ThreadEventType represents the kind of change a ThreadListWatcher noticed on a thread
+type ThreadEventType string
+
+// ThreadEventType constants
+const (
+	ThreadCreated ThreadEventType = "thread.created"
+	ThreadEdited  ThreadEventType = "thread.edited"
+	ThreadClosed  ThreadEventType = "thread.closed"
+	ThreadDeleted ThreadEventType = "thread.deleted"
+)
+
+// ThreadEvent models a change noticed by a ThreadListWatcher
+type ThreadEvent struct {
+	Type   ThreadEventType `json:"type"`
+	Thread *Thread         `json:"thread"`
+	At     time.Time       `json:"at"`
+}
+
+/*
+ThreadListWatcher polls ThreadList for the threads of a forum, and emits an event for every new thread (ThreadCreated),
+and for threads that get edited (Title or Message change), closed (IsClosed becomes true) or deleted (IsDeleted becomes
+true, or the thread is no longer listed).
+
+It works like Watcher: every Interval a poll lists the threads created since the watermark, and every RecheckInterval a
+recheck lists the threads created since Lookback before the watermark, so that changes to threads that recent are
+noticed. A zero Lookback disables rechecks. The watermark can be saved to a Store, and the first poll is never a
+recheck. Every page of 100 threads listed costs a request.
+
+Values are added to the ThreadList parameters: e.g. include=deleted lets deleted threads be listed. Events are passed
+to handlers in order, and errors met by Run to OnError, if set.
+*/
+type ThreadListWatcher struct {
+	gisqus          *Gisqus
+	Forum           string
+	Interval        time.Duration
+	RecheckInterval time.Duration
+	Lookback        time.Duration
+	Values          url.Values
+	Store           WatermarkStore
+	OnError         func(error)
+
+	// polling is serialized by pollMu, mu guards handlers and mark, so that handlers can call the ThreadListWatcher
+	pollMu    sync.Mutex
+	mu        sync.Mutex
+	handlers  []func(*ThreadEvent)
+	mark      *Watermark
+	threads   map[string]*Thread
+	rechecked time.Time
+}
+
+/*
+NewThreadListWatcher returns a ThreadListWatcher for the threads of forum, polling every minute for new threads, and
+rechecking the threads of the last day every 10 minutes
+*/
+func NewThreadListWatcher(g *Gisqus, forum string) *ThreadListWatcher {
+	return &ThreadListWatcher{
+		gisqus:          g,
+		Forum:           forum,
+		Interval:        time.Minute,
+		RecheckInterval: 10 * time.Minute,
+		Lookback:        24 * time.Hour,
+		Values:          url.Values{},
+	}
+}
+
+// Handle registers a function called with every event
+func (w *ThreadListWatcher) Handle(handler func(*ThreadEvent)) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+}
+
+// Notify registers a channel events are sent to. Sends block, so the channel must be drained.
+func (w *ThreadListWatcher) Notify(events chan<- *ThreadEvent) {
+	w.Handle(func(event *ThreadEvent) {
+		events <- event
+	})
+}
+
+// Watermark returns how far the ThreadListWatcher got, or nil before the first poll
+func (w *ThreadListWatcher) Watermark() *Watermark {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.mark == nil {
+		return nil
+	}
+	return &Watermark{Since: w.mark.Since, IDs: append([]string(nil), w.mark.IDs...)}
+}
+
+// Run polls until ctx is done, and returns ctx's error
+func (w *ThreadListWatcher) Run(ctx context.Context) error {
+	return runPolls(ctx, w.Interval, w.Poll, w.OnError)
+}
+
+/*
+Poll lists the threads once (rechecking them if RecheckInterval has elapsed since the last recheck), emits the events
+it finds, and saves the watermark if it moved. If listing fails, nothing is emitted and the next poll starts over from
+the same watermark.
+*/
+func (w *ThreadListWatcher) Poll(ctx context.Context) error {
+
+	if w.Forum == "" {
+		return errors.New("Must provide a forum")
+	}
+
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	now := time.Now().UTC()
+	w.mu.Lock()
+	current := w.mark
+	w.mu.Unlock()
+	plan, err := planPoll(current, w.Store, w.Lookback, w.RecheckInterval, w.rechecked, now)
+	if err != nil {
+		return err
+	}
+	if w.threads == nil {
+		w.threads = make(map[string]*Thread)
+	}
+
+	values := cloneValues(w.Values)
+	values.Set("forum", w.Forum)
+	values.Set("since", ToDisqusTime(plan.since))
+	values.Set("order", string(OrderAsc))
+	values.Set("limit", "100")
+
+	var threads []*Thread
+	for thread, err := range w.gisqus.ThreadListAll(ctx, values) {
+		if err != nil {
+			return err
+		}
+		threads = append(threads, thread)
+	}
+	if plan.fresh || plan.recheck {
+		w.rechecked = now
+	}
+
+	var events []*ThreadEvent
+	emit := func(t ThreadEventType, thread *Thread) {
+		events = append(events, &ThreadEvent{Type: t, Thread: thread, At: now})
+	}
+	gone, mark := diffPolled(w.threads, threads, plan, func(thread *Thread) (string, time.Time) {
+		return thread.ID, thread.CreatedAt
+	}, func(prev, thread *Thread) {
+		if thread.Title != prev.Title || thread.Message != prev.Message {
+			emit(ThreadEdited, thread)
+		}
+		if thread.IsClosed && !prev.IsClosed {
+			emit(ThreadClosed, thread)
+		}
+		if thread.IsDeleted && !prev.IsDeleted {
+			emit(ThreadDeleted, thread)
+		}
+	}, func(thread *Thread) {
+		emit(ThreadCreated, thread)
+	})
+	for _, thread := range gone {
+		if !thread.IsDeleted {
+			emit(ThreadDeleted, thread)
+		}
+	}
+
+	w.mu.Lock()
+	handlers := append([](func(*ThreadEvent)){}, w.handlers...)
+	w.mark = mark
+	w.mu.Unlock()
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+
+	if plan.moved(mark) && w.Store != nil {
+		return w.Store.Save(mark)
+	}
+	return nil
+}
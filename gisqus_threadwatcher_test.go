@@ -0,0 +1,76 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestThreadListWatcher(t *testing.T) {
+
+	t0 := time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+	threads := []map[string]interface{}{
+		{"id": "1", "createdAt": ToDisqusTime(t0), "title": "first"},
+		{"id": "2", "createdAt": ToDisqusTime(t0.Add(time.Minute)), "title": "second"},
+	}
+	g := NewGisqus("secret")
+	g.Use(listedSince(&threads))
+
+	store := NewFileWatermarkStore(filepath.Join(t.TempDir(), "watermark.json"))
+	err := store.Save(&Watermark{Since: t0, IDs: []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []*ThreadEvent
+	w := NewThreadListWatcher(&g, "tmz")
+	w.Store = store
+	w.RecheckInterval = 0
+	w.Handle(func(event *ThreadEvent) {
+		events = append(events, event)
+	})
+
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != ThreadCreated || events[0].Thread.ID != "2" {
+		t.Fatal("Should emit threads created after the watermark")
+	}
+	if mark := w.Watermark(); !mark.Since.Equal(t0.Add(time.Minute)) || len(mark.IDs) != 1 || mark.IDs[0] != "2" {
+		t.Fatal("Should move the watermark")
+	}
+
+	events = nil
+	threads[0]["title"] = "first, edited"
+	threads[1]["isClosed"] = true
+	threads = append(threads, map[string]interface{}{"id": "3", "createdAt": ToDisqusTime(t0.Add(time.Minute))})
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]ThreadEventType)
+	for _, event := range events {
+		types[event.Thread.ID] = event.Type
+	}
+	if len(events) != 3 || types["1"] != ThreadEdited || types["2"] != ThreadClosed || types["3"] != ThreadCreated {
+		t.Fatal("Should emit edits, closures and threads created in the same second as the watermark")
+	}
+
+	events = nil
+	threads = threads[1:]
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != ThreadDeleted || events[0].Thread.ID != "1" {
+		t.Fatal("Should emit threads no longer listed as deleted")
+	}
+
+	if NewThreadListWatcher(&g, "").Poll(testCtx) == nil {
+		t.Fatal("Should not watch without a forum")
+	}
+}
@@ -0,0 +1,202 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"sort"
+	"strconv"
+)
+
+// ThreadNode is a post in a ThreadTree
+type ThreadNode struct {
+	Post     *Post
+	Parent   *ThreadNode
+	Children []*ThreadNode
+	// Depth is 0 for roots, 1 for their replies and so on
+	Depth int
+	// Orphan is true for replies whose parent is not among the posts the tree was built from (e.g. it was deleted)
+	Orphan bool
+}
+
+// ThreadTree models the conversation of a thread, as a forest of posts and their replies
+type ThreadTree struct {
+	Roots []*ThreadNode
+	nodes map[string]*ThreadNode
+}
+
+// TreeOrder represents the possible orders of replies in a ThreadTree
+type TreeOrder int
+
+// TreeOrder constants. Replies are ordered oldest first by time, and best first by points (ties oldest first).
+const (
+	TreeOrderTime TreeOrder = iota
+	TreeOrderPoints
+)
+
+/*
+BuildThreadTree rebuilds the conversation of posts (e.g. returned by ThreadPosts) by linking every post to its
+parent. Posts whose parent is missing become roots, marked as orphans. Roots and replies are ordered by time.
+*/
+func BuildThreadTree(posts []*Post) *ThreadTree {
+
+	tree := &ThreadTree{
+		nodes: make(map[string]*ThreadNode, len(posts)),
+	}
+	for _, post := range posts {
+		if _, ok := tree.nodes[post.ID]; !ok {
+			tree.nodes[post.ID] = &ThreadNode{Post: post}
+		}
+	}
+	for _, node := range tree.nodes {
+		if node.Post.Parent == 0 {
+			continue
+		}
+		parent, ok := tree.nodes[strconv.Itoa(node.Post.Parent)]
+		if !ok || parent == node {
+			node.Orphan = true
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	for _, node := range tree.nodes {
+		if node.Parent == nil {
+			tree.Roots = append(tree.Roots, node)
+		}
+	}
+
+	tree.setDepths()
+	// posts in a reply cycle are not reachable from any root: the cycle is broken at its oldest post
+	for len(tree.nodes) > tree.Size() {
+		var oldest *ThreadNode
+		for _, node := range tree.nodes {
+			if node.Depth < 0 && (oldest == nil || node.Post.CreatedAt.Before(oldest.Post.CreatedAt)) {
+				oldest = node
+			}
+		}
+		oldest.Parent.Children = removeNode(oldest.Parent.Children, oldest)
+		oldest.Parent = nil
+		oldest.Orphan = true
+		tree.Roots = append(tree.Roots, oldest)
+		tree.setDepths()
+	}
+
+	tree.Sort(TreeOrderTime)
+	return tree
+}
+
+func removeNode(nodes []*ThreadNode, node *ThreadNode) []*ThreadNode {
+	for i, n := range nodes {
+		if n == node {
+			return append(nodes[:i], nodes[i+1:]...)
+		}
+	}
+	return nodes
+}
+
+func (t *ThreadTree) setDepths() {
+	for _, node := range t.nodes {
+		node.Depth = -1
+	}
+	t.Walk(func(node *ThreadNode) bool {
+		if node.Parent != nil {
+			node.Depth = node.Parent.Depth + 1
+		} else {
+			node.Depth = 0
+		}
+		return true
+	})
+}
+
+// Sort orders roots and replies at every level
+func (t *ThreadTree) Sort(order TreeOrder) {
+
+	less := func(nodes []*ThreadNode) func(i, j int) bool {
+		return func(i, j int) bool {
+			a, b := nodes[i].Post, nodes[j].Post
+			if order == TreeOrderPoints && a.Points != b.Points {
+				return a.Points > b.Points
+			}
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.Before(b.CreatedAt)
+			}
+			return a.ID < b.ID
+		}
+	}
+	sort.SliceStable(t.Roots, less(t.Roots))
+	for _, node := range t.nodes {
+		sort.SliceStable(node.Children, less(node.Children))
+	}
+}
+
+// Find returns the node of the post with id, or nil
+func (t *ThreadTree) Find(id string) *ThreadNode {
+	return t.nodes[id]
+}
+
+// Size returns the number of posts reachable from the roots of the tree
+func (t *ThreadTree) Size() int {
+	size := 0
+	t.Walk(func(*ThreadNode) bool {
+		size++
+		return true
+	})
+	return size
+}
+
+// MaxDepth returns the depth of the deepest reply, or -1 for an empty tree
+func (t *ThreadTree) MaxDepth() int {
+	max := -1
+	t.Walk(func(node *ThreadNode) bool {
+		if node.Depth > max {
+			max = node.Depth
+		}
+		return true
+	})
+	return max
+}
+
+// Walk visits the tree depth first, every post before its replies. Returning false from fn stops the visit.
+func (t *ThreadTree) Walk(fn func(*ThreadNode) bool) {
+	for _, root := range t.Roots {
+		if !root.walk(fn) {
+			return
+		}
+	}
+}
+
+// WalkBreadthFirst visits the tree level by level. Returning false from fn stops the visit.
+func (t *ThreadTree) WalkBreadthFirst(fn func(*ThreadNode) bool) {
+	queue := append([]*ThreadNode(nil), t.Roots...)
+	for len(queue) > 0 {
+		node := queue[0]
+		queue = queue[1:]
+		if !fn(node) {
+			return
+		}
+		queue = append(queue, node.Children...)
+	}
+}
+
+// walk visits the subtree rooted at n depth first. It returns false if the visit was stopped by fn.
+func (n *ThreadNode) walk(fn func(*ThreadNode) bool) bool {
+	if !fn(n) {
+		return false
+	}
+	for _, child := range n.Children {
+		if !child.walk(fn) {
+			return false
+		}
+	}
+	return true
+}
+
+// Ancestors returns the parent of n, its parent and so on up to the root
+func (n *ThreadNode) Ancestors() []*ThreadNode {
+	var ancestors []*ThreadNode
+	for p := n.Parent; p != nil; p = p.Parent {
+		ancestors = append(ancestors, p)
+	}
+	return ancestors
+}
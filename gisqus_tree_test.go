@@ -0,0 +1,91 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"testing"
+	"time"
+)
+
+func treePost(id string, parent int, minute int, points int) *Post {
+	post := &Post{Parent: parent}
+	post.ID = id
+	post.Points = points
+	post.CreatedAt = time.Date(2017, 5, 23, 10, minute, 0, 0, time.UTC)
+	return post
+}
+
+func TestBuildThreadTree(t *testing.T) {
+
+	posts := []*Post{
+		treePost("3", 1, 3, 0),
+		treePost("1", 0, 1, 0),
+		treePost("2", 1, 2, 5),
+		treePost("4", 3, 4, 0),
+		treePost("5", 99, 5, 0),
+		treePost("6", 0, 0, 0),
+	}
+	tree := BuildThreadTree(posts)
+
+	if len(tree.Roots) != 3 || tree.Roots[0].Post.ID != "6" || tree.Roots[1].Post.ID != "1" {
+		t.Fatal("Should order roots by time")
+	}
+	orphan := tree.Find("5")
+	if !orphan.Orphan || orphan.Parent != nil || orphan.Depth != 0 {
+		t.Fatal("Should make posts with a missing parent orphan roots")
+	}
+	one := tree.Find("1")
+	if len(one.Children) != 2 || one.Children[0].Post.ID != "2" {
+		t.Fatal("Should order replies by time")
+	}
+	four := tree.Find("4")
+	if four.Depth != 2 || len(four.Ancestors()) != 2 || four.Ancestors()[1] != one {
+		t.Fatal("Should compute depth and ancestors")
+	}
+	if tree.MaxDepth() != 2 || tree.Size() != 6 {
+		t.Fatal("Should compute max depth and size")
+	}
+
+	var visited []string
+	tree.Walk(func(node *ThreadNode) bool {
+		visited = append(visited, node.Post.ID)
+		return true
+	})
+	if len(visited) != 6 || visited[1] != "1" || visited[2] != "2" || visited[3] != "3" || visited[4] != "4" {
+		t.Fatal("Should walk depth first", visited)
+	}
+	visited = nil
+	tree.WalkBreadthFirst(func(node *ThreadNode) bool {
+		visited = append(visited, node.Post.ID)
+		return len(visited) < 4
+	})
+	if len(visited) != 4 || visited[3] != "2" {
+		t.Fatal("Should walk breadth first and stop on false", visited)
+	}
+
+	tree.Sort(TreeOrderPoints)
+	if one.Children[0].Post.ID != "2" {
+		t.Fatal("Should order replies by points")
+	}
+	posts[2].Points = -1
+	tree.Sort(TreeOrderPoints)
+	if one.Children[0].Post.ID != "3" {
+		t.Fatal("Should order replies by points")
+	}
+}
+
+func TestBuildThreadTreeCycle(t *testing.T) {
+
+	tree := BuildThreadTree([]*Post{
+		treePost("1", 2, 1, 0),
+		treePost("2", 1, 2, 0),
+		treePost("3", 3, 3, 0),
+	})
+	if tree.Size() != 3 || len(tree.Roots) != 2 {
+		t.Fatal("Should break reply cycles")
+	}
+	if tree.Find("2").Depth != 1 || !tree.Find("1").Orphan || !tree.Find("3").Orphan {
+		t.Fatal("Should break cycles at their oldest post")
+	}
+}
@@ -61,15 +61,13 @@ func (gisqus *Gisqus) UserActivities(ctx context.Context, userID string, values
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("user", userID)
 	values.Set("related", "")
 
-	url := usersUrls.ActivityURL + "?" + values.Encode()
-
 	var arr activityResponseRaw
 
-	err := gisqus.callAndInflate(ctx, url, &arr)
+	err := gisqus.callAndInflate(ctx, "users/listActivity", usersUrls.ActivityURL, values, &arr)
 	if err != nil {
 		return nil, err
 	}
@@ -123,13 +121,12 @@ func (gisqus *Gisqus) UserMostActiveForums(ctx context.Context, userID string, v
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("user", userID)
-	url := usersUrls.MostActiveForumsURL + "?" + values.Encode()
 
 	var mafr MostActiveForumsResponse
 
-	err := gisqus.callAndInflate(ctx, url, &mafr)
+	err := gisqus.callAndInflate(ctx, "users/listMostActiveForums", usersUrls.MostActiveForumsURL, values, &mafr)
 	if err != nil {
 		return nil, err
 	}
@@ -152,13 +149,12 @@ func (gisqus *Gisqus) UserPosts(ctx context.Context, userID string, values url.V
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
-	values.Set("api_secret", gisqus.secret)
+	values = cloneValues(values)
 	values.Set("user", userID)
-	url := usersUrls.PostListURL + "?" + values.Encode()
 
 	var plr PostListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &plr)
+	err := gisqus.callAndInflate(ctx, "users/listPosts", usersUrls.PostListURL, values, &plr)
 	if err != nil {
 		return nil, err
 	}
@@ -186,10 +182,10 @@ func (gisqus *Gisqus) UserDetails(ctx context.Context, userID string, values url
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
-	values.Set("api_secret", gisqus.secret)
-	url := usersUrls.DetailURL + "?" + values.Encode()
+	values = cloneValues(values)
+	values.Set("user", userID)
 	var udr UserDetailsResponse
-	err := gisqus.callAndInflate(ctx, url, &udr)
+	err := gisqus.callAndInflate(ctx, "users/details", usersUrls.DetailURL, values, &udr)
 	if err != nil {
 		return nil, err
 	}
@@ -206,11 +202,9 @@ UserInteresting wraps https://disqus.com/api/docs/users/interestingUsers/ (https
 */
 func (gisqus *Gisqus) UserInteresting(ctx context.Context, values url.Values) (*InterestingUsersResponse, error) {
 
-	values.Set("api_secret", gisqus.secret)
-	url := usersUrls.InterestingIUsersURL + "?" + values.Encode()
 	var iur InterestingUsersResponse
 
-	err := gisqus.callAndInflate(ctx, url, &iur)
+	err := gisqus.callAndInflate(ctx, "users/interestingUsers", usersUrls.InterestingIUsersURL, values, &iur)
 	if err != nil {
 		return nil, err
 	}
@@ -232,12 +226,11 @@ func (gisqus *Gisqus) UserActiveForums(ctx context.Context, userID string, value
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
+	values = cloneValues(values)
 	values.Set("user", userID)
-	values.Set("api_secret", gisqus.secret)
-	url := usersUrls.ActiveForumsURL + "?" + values.Encode()
 
 	var afr ActiveForumsResponse
-	err := gisqus.callAndInflate(ctx, url, &afr)
+	err := gisqus.callAndInflate(ctx, "users/listActiveForums", usersUrls.ActiveForumsURL, values, &afr)
 	if err != nil {
 		return nil, err
 	}
@@ -259,12 +252,11 @@ func (gisqus *Gisqus) UserFollowers(ctx context.Context, userID string, values u
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
+	values = cloneValues(values)
 	values.Set("user", userID)
-	values.Set("api_secret", gisqus.secret)
-	url := usersUrls.FollowersURL + "?" + values.Encode()
 	var fr UserListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &fr)
+	err := gisqus.callAndInflate(ctx, "users/listFollowers", usersUrls.FollowersURL, values, &fr)
 	if err != nil {
 		return nil, err
 	}
@@ -286,12 +278,11 @@ func (gisqus *Gisqus) UserFollowing(ctx context.Context, userID string, values u
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
+	values = cloneValues(values)
 	values.Set("user", userID)
-	values.Set("api_secret", gisqus.secret)
-	url := usersUrls.FollowingURL + "?" + values.Encode()
 	var fr UserListResponse
 
-	err := gisqus.callAndInflate(ctx, url, &fr)
+	err := gisqus.callAndInflate(ctx, "users/listFollowing", usersUrls.FollowingURL, values, &fr)
 	if err != nil {
 		return nil, err
 	}
@@ -313,12 +304,11 @@ func (gisqus *Gisqus) UserForumFollowing(ctx context.Context, userID string, val
 	if userID == "" {
 		return nil, errors.New("Must provide a user id")
 	}
+	values = cloneValues(values)
 	values.Set("user", userID)
-	values.Set("api_secret", gisqus.secret)
-	url := usersUrls.FollowingForumsURL + "?" + values.Encode()
 
 	var uffr UserForumFollowingResponse
-	err := gisqus.callAndInflate(ctx, url, &uffr)
+	err := gisqus.callAndInflate(ctx, "users/listFollowingForums", usersUrls.FollowingForumsURL, values, &uffr)
 	if err != nil {
 		return nil, err
 	}
@@ -4,7 +4,9 @@
 package gisqus
 
 import (
+	"context"
 	"fmt"
+	"net/url"
 	"os"
 	"testing"
 )
@@ -177,6 +179,25 @@ func TestUserDetails(t *testing.T) {
 	}
 }
 
+func TestUserDetailsSendsUser(t *testing.T) {
+
+	var sent string
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			sent = req.Values.Get("user")
+			return next.Do(ctx, req)
+		})
+	})
+	_, err := g.UserDetails(testCtx, "79849", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sent != "79849" {
+		t.Fatal("Should send the user id to Disqus")
+	}
+}
+
 func TestUserInteresting(t *testing.T) {
 
 	users, err := testGisqus.UserInteresting(testCtx, testValues)
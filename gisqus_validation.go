@@ -0,0 +1,109 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamProblem describes a problem with a parameter of a call
+type ParamProblem struct {
+	Param   string
+	Message string
+}
+
+// ValidationError is returned by calls whose parameters would be rejected by Disqus. It lists every problem found.
+type ValidationError struct {
+	Endpoint string
+	Problems []ParamProblem
+}
+
+func (e *ValidationError) Error() string {
+
+	problems := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		problems[i] = p.Param + ": " + p.Message
+	}
+	return fmt.Sprintf("invalid parameters for %s: %s", e.Endpoint, strings.Join(problems, "; "))
+}
+
+// maximum value of the limit parameter, by endpoint. Endpoints not listed accept up to 100
+var maxLimits = map[string]int{
+	"trends/listThreads": 10,
+}
+
+// endpoints that accept either a forum or a thread, but not both
+var forumOrThread = map[string]bool{
+	"posts/list":        true,
+	"posts/listPopular": true,
+}
+
+var intervals = map[string]bool{
+	string(Interval1h):  true,
+	string(Interval6h):  true,
+	string(Interval12h): true,
+	string(Interval1d):  true,
+	string(Interval3d):  true,
+	string(Interval7d):  true,
+	string(Interval30d): true,
+	string(Interval90d): true,
+}
+
+func validate(endpoint string, values url.Values) error {
+
+	var problems []ParamProblem
+	problem := func(param, format string, args ...interface{}) {
+		problems = append(problems, ParamProblem{Param: param, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if limit, ok := values["limit"]; ok {
+		max, ok := maxLimits[endpoint]
+		if !ok {
+			max = 100
+		}
+		n, err := strconv.Atoi(limit[0])
+		if err != nil || n < 1 || n > max {
+			problem("limit", "must be between 1 and %d, got %q", max, limit[0])
+		}
+	}
+	if interval, ok := values["interval"]; ok && !intervals[interval[0]] {
+		problem("interval", "must be one of the Interval constants, got %q", interval[0])
+	}
+	if since, ok := values["since"]; ok && !validSince(since[0]) {
+		problem("since", "must be a unix timestamp, a date formatted with ToDisqusTime or an Interval, got %q", since[0])
+	}
+	if order, ok := values["order"]; ok && order[0] != string(OrderAsc) && order[0] != string(OrderDesc) {
+		problem("order", "must be %s or %s, got %q", OrderAsc, OrderDesc, order[0])
+	}
+	if forumOrThread[endpoint] && values.Get("forum") != "" && values.Get("thread") != "" {
+		problem("forum", "cannot be used together with thread")
+	}
+	if endpoint == "threads/set" && len(values["thread"]) > ThreadSetMaxIDs {
+		problem("thread", "at most %d threads can be requested at once, got %d (see ThreadSetAll)", ThreadSetMaxIDs, len(values["thread"]))
+	}
+
+	if problems != nil {
+		return &ValidationError{
+			Endpoint: endpoint,
+			Problems: problems,
+		}
+	}
+	return nil
+}
+
+func validSince(since string) bool {
+
+	if intervals[since] {
+		return true
+	}
+	if _, err := strconv.ParseInt(since, 10, 64); err == nil {
+		return true
+	}
+	_, err := time.Parse(DisqusDateFormat, since)
+	return err == nil
+}
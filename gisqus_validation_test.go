@@ -0,0 +1,64 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+)
+
+func TestValidation(t *testing.T) {
+
+	var calls int
+	g := countingGisqus(&calls)
+
+	values := url.Values{}
+	values.Set("limit", "500")
+	values.Set("order", "up")
+	values.Set("since", "yesterday")
+	values.Set("forum", "tmz")
+	values.Set("thread", "5843656825")
+
+	_, err := g.PostList(testCtx, values)
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatal("Should return a validation error")
+	}
+	if verr.Endpoint != "posts/list" {
+		t.Fatal("Should report the endpoint")
+	}
+	params := map[string]bool{}
+	for _, p := range verr.Problems {
+		params[p.Param] = true
+	}
+	if len(params) != 4 || !params["limit"] || !params["order"] || !params["since"] || !params["forum"] {
+		t.Fatal("Should report every problem", verr)
+	}
+	if calls != 0 {
+		t.Fatal("Should not call Disqus with invalid parameters")
+	}
+
+	values = url.Values{}
+	values.Set("interval", "2d")
+	values.Set("limit", "20")
+	_, err = g.ThreadTrending(testCtx, values)
+	if !errors.As(err, &verr) || len(verr.Problems) != 2 {
+		t.Fatal("Should validate intervals and endpoint specific limits")
+	}
+
+	values = url.Values{}
+	values.Set("interval", string(Interval7d))
+	values.Set("limit", "10")
+	values.Set("order", string(OrderAsc))
+	values.Set("since", "2017-05-23T17:57:41")
+	_, err = g.ThreadTrending(testCtx, values)
+	if err != nil {
+		t.Fatal(err)
+	}
+	values.Set("since", "1495560000")
+	if validate("posts/list", values) != nil {
+		t.Fatal("Should accept unix timestamps in since")
+	}
+}
@@ -0,0 +1,412 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// PostEventType represents the kind of change a Watcher noticed on a post
+type PostEventType string
+
+// PostEventType constants
+const (
+	PostCreated PostEventType = "post.created"
+	PostEdited  PostEventType = "post.edited"
+	PostDeleted PostEventType = "post.deleted"
+	PostFlagged PostEventType = "post.flagged"
+)
+
+// PostEvent models a change noticed by a Watcher
+type PostEvent struct {
+	Type PostEventType `json:"type"`
+	Post *Post         `json:"post"`
+	At   time.Time     `json:"at"`
+}
+
+/*
+Watermark models how far a Watcher (or a ThreadListWatcher) got: the creation time of the newest post (or thread) seen,
+and the ids of the ones created at that time (Disqus times have a resolution of one second, and since is inclusive).
+*/
+type Watermark struct {
+	Since time.Time `json:"since"`
+	IDs   []string  `json:"ids"`
+}
+
+// WatermarkStore persists the watermark of a Watcher across restarts. Load returns nil if nothing was saved yet.
+type WatermarkStore interface {
+	Load() (*Watermark, error)
+	Save(*Watermark) error
+}
+
+// FileWatermarkStore is a WatermarkStore keeping the watermark as JSON in a file
+type FileWatermarkStore struct {
+	path string
+}
+
+// NewFileWatermarkStore returns a FileWatermarkStore saving to path
+func NewFileWatermarkStore(path string) *FileWatermarkStore {
+	return &FileWatermarkStore{path: path}
+}
+
+// Load reads the watermark from the file, if it exists
+func (s *FileWatermarkStore) Load() (*Watermark, error) {
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var mark Watermark
+	err = json.Unmarshal(data, &mark)
+	if err != nil {
+		return nil, err
+	}
+	return &mark, nil
+}
+
+// Save writes the watermark to a temporary file, then renames it, so that the file is never left half written
+func (s *FileWatermarkStore) Save(mark *Watermark) error {
+
+	data, err := json.Marshal(mark)
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), filepath.Base(s.path)+".*")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
+
+/*
+Watcher polls PostList for the posts of a forum or of a thread, and emits an event for every new post (PostCreated),
+and for posts that get edited (IsEdited or Message change), deleted (IsDeleted becomes true, or the post is no longer
+listed) or flagged (IsFlagged becomes true).
+
+Every Interval, a poll lists the posts created since the watermark (with since and order=asc). Every RecheckInterval,
+a recheck lists instead the posts created since Lookback before the watermark, so that changes to posts that recent are
+noticed: edits, deletions and flags of older posts go unnoticed, and posts are only reported as deleted for no longer
+being listed by a recheck. A zero Lookback disables rechecks. Posts at or before the watermark are never reported as
+created, so a Watcher restarted with the same Store does not replay nor miss posts. Without a saved watermark, the
+Watcher starts from the time of its first poll, which is never a recheck.
+
+Every page of 100 posts listed costs a request: a poll usually costs one, a recheck one per 100 posts created during
+Lookback. With the defaults, a forum receiving 1000 posts an hour costs about 60 requests an hour for polls and 60 for
+rechecks, out of the default quota of 1000 requests an hour.
+
+Values are added to the PostList parameters: e.g. include=deleted lets deleted posts be listed (which requires moderator
+rights). Events are passed to handlers in order, and errors met by Run to OnError, if set.
+*/
+type Watcher struct {
+	gisqus          *Gisqus
+	Forum           string
+	Thread          string
+	Interval        time.Duration
+	RecheckInterval time.Duration
+	Lookback        time.Duration
+	Values          url.Values
+	Store           WatermarkStore
+	OnError         func(error)
+
+	// polling is serialized by pollMu, mu guards handlers and mark, so that handlers can call the Watcher
+	pollMu    sync.Mutex
+	mu        sync.Mutex
+	handlers  []func(*PostEvent)
+	mark      *Watermark
+	posts     map[string]*Post
+	rechecked time.Time
+}
+
+/*
+NewWatcher returns a Watcher for the posts of forum, polling every minute for new posts, and rechecking the posts of the
+last hour every 10 minutes
+*/
+func NewWatcher(g *Gisqus, forum string) *Watcher {
+	return &Watcher{
+		gisqus:          g,
+		Forum:           forum,
+		Interval:        time.Minute,
+		RecheckInterval: 10 * time.Minute,
+		Lookback:        time.Hour,
+		Values:          url.Values{},
+	}
+}
+
+// NewThreadWatcher returns a Watcher for the posts of thread, with the same settings as NewWatcher
+func NewThreadWatcher(g *Gisqus, thread string) *Watcher {
+
+	w := NewWatcher(g, "")
+	w.Thread = thread
+	return w
+}
+
+// Handle registers a function called with every event
+func (w *Watcher) Handle(handler func(*PostEvent)) {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+}
+
+// Notify registers a channel events are sent to. Sends block, so the channel must be drained.
+func (w *Watcher) Notify(events chan<- *PostEvent) {
+	w.Handle(func(event *PostEvent) {
+		events <- event
+	})
+}
+
+// Watermark returns how far the Watcher got, or nil before the first poll
+func (w *Watcher) Watermark() *Watermark {
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.mark == nil {
+		return nil
+	}
+	return &Watermark{Since: w.mark.Since, IDs: append([]string(nil), w.mark.IDs...)}
+}
+
+// Run polls until ctx is done, and returns ctx's error
+func (w *Watcher) Run(ctx context.Context) error {
+	return runPolls(ctx, w.Interval, w.Poll, w.OnError)
+}
+
+/*
+Poll lists the posts once (rechecking them if RecheckInterval has elapsed since the last recheck), emits the events it
+finds, and saves the watermark if it moved. If listing fails, nothing is emitted and the next poll starts over from the
+same watermark.
+*/
+func (w *Watcher) Poll(ctx context.Context) error {
+
+	if w.Forum == "" && w.Thread == "" {
+		return errors.New("Must provide a forum or a thread")
+	}
+
+	w.pollMu.Lock()
+	defer w.pollMu.Unlock()
+
+	now := time.Now().UTC()
+	w.mu.Lock()
+	current := w.mark
+	w.mu.Unlock()
+	plan, err := planPoll(current, w.Store, w.Lookback, w.RecheckInterval, w.rechecked, now)
+	if err != nil {
+		return err
+	}
+	if w.posts == nil {
+		w.posts = make(map[string]*Post)
+	}
+
+	values := cloneValues(w.Values)
+	if w.Forum != "" {
+		values.Set("forum", w.Forum)
+	}
+	if w.Thread != "" {
+		values.Set("thread", w.Thread)
+	}
+	values.Set("since", ToDisqusTime(plan.since))
+	values.Set("order", string(OrderAsc))
+	values.Set("limit", "100")
+
+	var posts []*Post
+	for post, err := range w.gisqus.PostListAll(ctx, values) {
+		if err != nil {
+			return err
+		}
+		posts = append(posts, post)
+	}
+	if plan.fresh || plan.recheck {
+		w.rechecked = now
+	}
+
+	var events []*PostEvent
+	emit := func(t PostEventType, post *Post) {
+		events = append(events, &PostEvent{Type: t, Post: post, At: now})
+	}
+	gone, mark := diffPolled(w.posts, posts, plan, func(post *Post) (string, time.Time) {
+		return post.ID, post.CreatedAt
+	}, func(prev, post *Post) {
+		if post.IsEdited != prev.IsEdited || post.Message != prev.Message {
+			emit(PostEdited, post)
+		}
+		if post.IsDeleted && !prev.IsDeleted {
+			emit(PostDeleted, post)
+		}
+		if post.IsFlagged && !prev.IsFlagged {
+			emit(PostFlagged, post)
+		}
+	}, func(post *Post) {
+		emit(PostCreated, post)
+		if post.IsFlagged {
+			emit(PostFlagged, post)
+		}
+	})
+	for _, post := range gone {
+		if !post.IsDeleted {
+			emit(PostDeleted, post)
+		}
+	}
+
+	w.mu.Lock()
+	handlers := append([](func(*PostEvent)){}, w.handlers...)
+	w.mark = mark
+	w.mu.Unlock()
+	for _, event := range events {
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+
+	if plan.moved(mark) && w.Store != nil {
+		return w.Store.Save(mark)
+	}
+	return nil
+}
+
+// runPolls calls poll every interval until ctx is done, passing errors to onError if set, and returns ctx's error
+func runPolls(ctx context.Context, interval time.Duration, poll func(context.Context) error, onError func(error)) error {
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		err := poll(ctx)
+		if err != nil && onError != nil && ctx.Err() == nil {
+			onError(err)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollPlan tells what a poll of a watcher lists
+type pollPlan struct {
+	// current is the watermark before the poll, fresh is true for the first poll of a watcher
+	current *Watermark
+	fresh   bool
+	// recheck is true if the poll lists the items created since window instead of since the watermark
+	recheck bool
+	since   time.Time
+	// items created before window are no longer tracked
+	window time.Time
+}
+
+/*
+planPoll loads the watermark on the first poll (from store if set, otherwise starting at now), and tells whether the
+poll is a recheck: the first poll never is, the next ones are if recheckInterval has elapsed since rechecked.
+*/
+func planPoll(current *Watermark, store WatermarkStore, lookback, recheckInterval time.Duration, rechecked, now time.Time) (*pollPlan, error) {
+
+	plan := &pollPlan{current: current}
+	if current == nil {
+		plan.fresh = true
+		if store != nil {
+			mark, err := store.Load()
+			if err != nil {
+				return nil, err
+			}
+			plan.current = mark
+		}
+		if plan.current == nil {
+			plan.current = &Watermark{Since: now.Truncate(time.Second)}
+		}
+	}
+	plan.window = plan.current.Since.Add(-lookback)
+	plan.recheck = !plan.fresh && lookback > 0 && now.Sub(rechecked) >= recheckInterval
+	plan.since = plan.current.Since
+	if plan.recheck {
+		plan.since = plan.window
+	}
+	return plan, nil
+}
+
+// moved tells whether mark is to be saved after the poll
+func (p *pollPlan) moved(mark *Watermark) bool {
+	return p.fresh || !mark.Since.Equal(p.current.Since) || len(mark.IDs) != len(p.current.IDs)
+}
+
+/*
+diffPolled compares the items listed by a poll with the tracked ones, and computes the new watermark. changed is called
+for items already tracked, created for untracked items newer than the watermark, in the order they are listed. Items
+created before the window of the poll are no longer tracked; the others are returned, oldest first, if a recheck no
+longer lists them.
+*/
+func diffPolled[T any](tracked map[string]T, items []T, plan *pollPlan, key func(T) (string, time.Time), changed func(prev, item T), created func(T)) ([]T, *Watermark) {
+
+	current := plan.current
+	known := make(map[string]bool, len(current.IDs))
+	for _, id := range current.IDs {
+		known[id] = true
+	}
+	mark := &Watermark{Since: current.Since, IDs: append([]string(nil), current.IDs...)}
+
+	listed := make(map[string]bool, len(items))
+	for _, item := range items {
+		id, createdAt := key(item)
+		if listed[id] {
+			continue
+		}
+		listed[id] = true
+		prev, ok := tracked[id]
+		tracked[id] = item
+
+		switch {
+		case ok:
+			changed(prev, item)
+		case createdAt.After(current.Since) || (createdAt.Equal(current.Since) && !known[id]):
+			created(item)
+		}
+
+		switch {
+		case createdAt.After(mark.Since):
+			mark.Since = createdAt
+			mark.IDs = []string{id}
+		case createdAt.Equal(mark.Since) && !known[id]:
+			known[id] = true
+			mark.IDs = append(mark.IDs, id)
+		}
+	}
+
+	var gone []T
+	for id, item := range tracked {
+		_, createdAt := key(item)
+		switch {
+		case createdAt.Before(plan.window):
+			delete(tracked, id)
+		case plan.recheck && !listed[id]:
+			delete(tracked, id)
+			gone = append(gone, item)
+		}
+	}
+	sort.Slice(gone, func(i, j int) bool {
+		idI, createdI := key(gone[i])
+		idJ, createdJ := key(gone[j])
+		if !createdI.Equal(createdJ) {
+			return createdI.Before(createdJ)
+		}
+		return idI < idJ
+	})
+	return gone, mark
+}
@@ -0,0 +1,188 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package gisqus
+
+import (
+	"context"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// listedSince answers list calls with the items (posts or threads) created since the since parameter
+func listedSince(items *[]map[string]interface{}) Middleware {
+
+	return func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+
+			since, _ := time.Parse(DisqusDateFormat, req.Values.Get("since"))
+			listed := []map[string]interface{}{}
+			for _, item := range *items {
+				createdAt, _ := time.Parse(DisqusDateFormat, item["createdAt"].(string))
+				if !createdAt.Before(since) {
+					listed = append(listed, item)
+				}
+			}
+			body, err := json.Marshal(map[string]interface{}{"code": 0, "cursor": map[string]interface{}{"hasNext": false}, "response": listed})
+			if err != nil {
+				return nil, err
+			}
+			return &Response{StatusCode: 200, Body: body}, nil
+		})
+	}
+}
+
+func TestWatcher(t *testing.T) {
+
+	t0 := time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+	posts := []map[string]interface{}{
+		{"id": "1", "createdAt": ToDisqusTime(t0), "message": "first"},
+		{"id": "2", "createdAt": ToDisqusTime(t0.Add(time.Minute)), "message": "second"},
+	}
+	g := NewGisqus("secret")
+	g.Use(listedSince(&posts))
+
+	store := NewFileWatermarkStore(filepath.Join(t.TempDir(), "watermark.json"))
+	err := store.Save(&Watermark{Since: t0, IDs: []string{"1"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var events []*PostEvent
+	w := NewWatcher(&g, "tmz")
+	w.Store = store
+	w.RecheckInterval = 0
+	w.Handle(func(event *PostEvent) {
+		events = append(events, event)
+	})
+
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != PostCreated || events[0].Post.ID != "2" {
+		t.Fatal("Should emit posts created after the watermark")
+	}
+	mark, err := store.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !mark.Since.Equal(t0.Add(time.Minute)) || len(mark.IDs) != 1 || mark.IDs[0] != "2" {
+		t.Fatal("Should save the watermark")
+	}
+
+	events = nil
+	posts[0]["isEdited"] = true
+	posts[0]["message"] = "first, edited"
+	posts[1]["isFlagged"] = true
+	posts = append(posts, map[string]interface{}{"id": "3", "createdAt": ToDisqusTime(t0.Add(time.Minute))})
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	types := make(map[string]PostEventType)
+	for _, event := range events {
+		types[event.Post.ID] = event.Type
+	}
+	if len(events) != 3 || types["1"] != PostEdited || types["2"] != PostFlagged || types["3"] != PostCreated {
+		t.Fatal("Should emit edits, flags and posts created in the same second as the watermark")
+	}
+
+	events = nil
+	posts = posts[1:]
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 || events[0].Type != PostDeleted || events[0].Post.ID != "1" {
+		t.Fatal("Should emit posts no longer listed as deleted")
+	}
+
+	events = nil
+	restarted := NewWatcher(&g, "tmz")
+	restarted.Store = store
+	restarted.RecheckInterval = 0
+	ch := make(chan *PostEvent, 10)
+	restarted.Notify(ch)
+	err = restarted.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ch) != 0 || len(restarted.Watermark().IDs) != 2 {
+		t.Fatal("Should not replay posts after a restart")
+	}
+
+	posts = append(posts, map[string]interface{}{"id": "4", "createdAt": ToDisqusTime(t0.Add(2 * time.Minute)), "isDeleted": true})
+	posts[0]["isDeleted"] = true
+	err = restarted.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ch) != 2 || (<-ch).Type != PostDeleted || (<-ch).Type != PostCreated {
+		t.Fatal("Should send events to channels")
+	}
+
+	if NewWatcher(&g, "").Poll(testCtx) == nil {
+		t.Fatal("Should not watch without a forum or a thread")
+	}
+}
+
+func TestWatcherRechecks(t *testing.T) {
+
+	t0 := time.Date(2017, 5, 23, 10, 0, 0, 0, time.UTC)
+	posts := []map[string]interface{}{
+		{"id": "1", "createdAt": ToDisqusTime(t0.Add(-30 * time.Minute))},
+		{"id": "2", "createdAt": ToDisqusTime(t0)},
+	}
+	var since []string
+	g := NewGisqus("secret")
+	g.Use(func(next Doer) Doer {
+		return DoerFunc(func(ctx context.Context, req *Request) (*Response, error) {
+			since = append(since, req.Values.Get("since"))
+			return next.Do(ctx, req)
+		})
+	})
+	g.Use(listedSince(&posts))
+
+	store := NewFileWatermarkStore(filepath.Join(t.TempDir(), "watermark.json"))
+	err := store.Save(&Watermark{Since: t0, IDs: []string{"2"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var events []*PostEvent
+	w := NewWatcher(&g, "tmz")
+	w.Store = store
+	w.Handle(func(event *PostEvent) {
+		events = append(events, event)
+	})
+
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	posts = posts[:1]
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if since[0] != ToDisqusTime(t0) || since[1] != ToDisqusTime(t0) {
+		t.Fatal("Should poll from the watermark between rechecks")
+	}
+	if len(events) != 0 {
+		t.Fatal("Should only report deletions on rechecks")
+	}
+
+	w.rechecked = time.Now().Add(-w.RecheckInterval)
+	err = w.Poll(testCtx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if since[2] != ToDisqusTime(t0.Add(-w.Lookback)) {
+		t.Fatal("Should recheck Lookback before the watermark")
+	}
+	if len(events) != 1 || events[0].Type != PostDeleted || events[0].Post.ID != "2" {
+		t.Fatal("Should report posts no longer listed by a recheck as deleted")
+	}
+}
@@ -0,0 +1,3 @@
+module github.com/pierods/gisqus
+
+go 1.23
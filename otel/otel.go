@@ -0,0 +1,118 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+/*
+Package otel instruments gisqus with OpenTelemetry. It provides a gisqus.Middleware that creates a span for every
+call made to Disqus and records request count, errors, latency and remaining quota:
+
+	mw, err := otel.NewMiddleware(tracerProvider, meterProvider)
+	if err != nil {
+		...
+	}
+	g.Use(mw)
+*/
+package otel
+
+import (
+	"context"
+	"time"
+
+	"github.com/pierods/gisqus"
+	gotel "go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/pierods/gisqus/otel"
+
+// Attribute keys set on spans and metrics
+const (
+	EndpointKey           = attribute.Key("disqus.endpoint")
+	StatusCodeKey         = attribute.Key("http.response.status_code")
+	CursorKey             = attribute.Key("disqus.cursor")
+	AttemptKey            = attribute.Key("disqus.attempt")
+	RatelimitRemainingKey = attribute.Key("disqus.ratelimit.remaining")
+)
+
+type instruments struct {
+	tracer    trace.Tracer
+	requests  metric.Int64Counter
+	errors    metric.Int64Counter
+	latency   metric.Float64Histogram
+	remaining metric.Int64Gauge
+}
+
+/*
+NewMiddleware returns a middleware instrumenting every Disqus call. Nil providers are replaced by the global ones.
+The middleware should be registered last with gisqus.Use, so that spans measure actual calls to Disqus.
+*/
+func NewMiddleware(tp trace.TracerProvider, mp metric.MeterProvider) (gisqus.Middleware, error) {
+
+	if tp == nil {
+		tp = gotel.GetTracerProvider()
+	}
+	if mp == nil {
+		mp = gotel.GetMeterProvider()
+	}
+	meter := mp.Meter(instrumentationName)
+
+	var err error
+	ins := instruments{tracer: tp.Tracer(instrumentationName)}
+
+	ins.requests, err = meter.Int64Counter("gisqus.requests", metric.WithDescription("Number of calls made to Disqus"))
+	if err != nil {
+		return nil, err
+	}
+	ins.errors, err = meter.Int64Counter("gisqus.errors", metric.WithDescription("Number of failed calls made to Disqus"))
+	if err != nil {
+		return nil, err
+	}
+	ins.latency, err = meter.Float64Histogram("gisqus.request.duration", metric.WithDescription("Duration of calls made to Disqus"), metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	ins.remaining, err = meter.Int64Gauge("gisqus.ratelimit.remaining", metric.WithDescription("Calls left before the Disqus rate limit is hit"))
+	if err != nil {
+		return nil, err
+	}
+
+	return ins.middleware, nil
+}
+
+func (ins *instruments) middleware(next gisqus.Doer) gisqus.Doer {
+
+	return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+
+		attrs := []attribute.KeyValue{
+			EndpointKey.String(req.Endpoint),
+			AttemptKey.Int(req.Attempt),
+		}
+		if cursor := req.Values.Get("cursor"); cursor != "" {
+			attrs = append(attrs, CursorKey.String(cursor))
+		}
+		ctx, span := ins.tracer.Start(ctx, "disqus "+req.Endpoint, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(attrs...))
+		defer span.End()
+
+		start := time.Now()
+		resp, err := next.Do(ctx, req)
+		elapsed := time.Since(start)
+
+		endpoint := metric.WithAttributes(EndpointKey.String(req.Endpoint))
+		if resp != nil {
+			span.SetAttributes(StatusCodeKey.Int(resp.StatusCode), RatelimitRemainingKey.Int(resp.Limits.RatelimitRemaining))
+			if resp.Limits.RatelimitLimit > 0 {
+				ins.remaining.Record(ctx, int64(resp.Limits.RatelimitRemaining))
+			}
+		}
+		ins.requests.Add(ctx, 1, endpoint)
+		ins.latency.Record(ctx, elapsed.Seconds(), endpoint)
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			ins.errors.Add(ctx, 1, endpoint)
+		}
+		return resp, err
+	})
+}
@@ -0,0 +1,124 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package otel
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/pierods/gisqus"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newTestGisqus(t *testing.T, status int) (*gisqus.Gisqus, *tracetest.SpanRecorder, *sdkmetric.ManualReader) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "999")
+		w.Header().Set("X-Ratelimit-Limit", "1000")
+		w.Header().Set("X-Ratelimit-Reset", "1495785600")
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"code":0,"response":{"id":"1","createdAt":"2017-05-23T17:57:41"}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	g := gisqus.NewGisqus("secret")
+	urls := g.ReadThreadsURLs()
+	urls.ThreadDetailURL = server.URL + "/api/3.0/threads/details.json"
+	g.SetThreadsURLs(urls)
+
+	recorder := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	mw, err := NewMiddleware(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.Use(mw)
+	return &g, recorder, reader
+}
+
+func collect(t *testing.T, reader *sdkmetric.ManualReader) map[string]metricdata.Aggregation {
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(context.Background(), &rm); err != nil {
+		t.Fatal(err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+	return metrics
+}
+
+func TestSpansAndMetrics(t *testing.T) {
+
+	g, recorder, reader := newTestGisqus(t, 200)
+
+	values := url.Values{}
+	values.Set("cursor", "1:0:0")
+	_, err := g.ThreadDetails(context.Background(), "1", values)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatal("Should create a span per call")
+	}
+	if spans[0].Name() != "disqus threads/details" {
+		t.Fatal("Should name spans after the endpoint")
+	}
+	attrs := map[string]string{}
+	for _, kv := range spans[0].Attributes() {
+		attrs[string(kv.Key)] = kv.Value.Emit()
+	}
+	if attrs["disqus.endpoint"] != "threads/details" || attrs["disqus.cursor"] != "1:0:0" || attrs["disqus.attempt"] != "1" {
+		t.Fatal("Should set endpoint, cursor and attempt on spans", attrs)
+	}
+	if attrs["http.response.status_code"] != "200" || attrs["disqus.ratelimit.remaining"] != "999" {
+		t.Fatal("Should set status and remaining quota on spans", attrs)
+	}
+
+	metrics := collect(t, reader)
+	requests, ok := metrics["gisqus.requests"].(metricdata.Sum[int64])
+	if !ok || requests.DataPoints[0].Value != 1 {
+		t.Fatal("Should count requests")
+	}
+	if _, ok := metrics["gisqus.errors"]; ok {
+		t.Fatal("Should not count errors on successful calls")
+	}
+	if _, ok := metrics["gisqus.request.duration"].(metricdata.Histogram[float64]); !ok {
+		t.Fatal("Should record latency")
+	}
+	remaining, ok := metrics["gisqus.ratelimit.remaining"].(metricdata.Gauge[int64])
+	if !ok || remaining.DataPoints[0].Value != 999 {
+		t.Fatal("Should record remaining quota")
+	}
+}
+
+func TestErrors(t *testing.T) {
+
+	g, recorder, reader := newTestGisqus(t, 500)
+
+	_, err := g.ThreadDetails(context.Background(), "1", url.Values{})
+	if err == nil {
+		t.Fatal("Should fail on server errors")
+	}
+	if recorder.Ended()[0].Status().Code != codes.Error {
+		t.Fatal("Should mark spans of failed calls as errors")
+	}
+	errors, ok := collect(t, reader)["gisqus.errors"].(metricdata.Sum[int64])
+	if !ok || errors.DataPoints[0].Value != 1 {
+		t.Fatal("Should count errors")
+	}
+}
@@ -0,0 +1,105 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+/*
+Package prometheus exports Disqus rate limits and gisqus call statistics to Prometheus:
+
+	collector := prometheus.NewCollector(&g)
+	registry.MustRegister(collector)
+*/
+package prometheus
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pierods/gisqus"
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	remainingDesc = prom.NewDesc("gisqus_ratelimit_remaining", "Calls left before the Disqus rate limit is hit.", nil, nil)
+	limitDesc     = prom.NewDesc("gisqus_ratelimit_limit", "Disqus rate limit for the account.", nil, nil)
+	resetDesc     = prom.NewDesc("gisqus_ratelimit_reset_seconds", "Seconds until the Disqus rate limit is reset.", nil, nil)
+)
+
+// Collector is a prometheus.Collector for a Gisqus instance
+type Collector struct {
+	requests *prom.CounterVec
+	errors   *prom.CounterVec
+
+	mu     sync.Mutex
+	limits gisqus.DisqusRateLimit
+	seen   bool
+}
+
+/*
+NewCollector returns a Collector for g. It registers a middleware with g, so that every call updates the rate limits
+and the per endpoint counters. Rate limit gauges are exported only after the first call has been made.
+*/
+func NewCollector(g *gisqus.Gisqus) *Collector {
+
+	c := &Collector{
+		requests: prom.NewCounterVec(prom.CounterOpts{
+			Name: "gisqus_requests_total",
+			Help: "Number of calls made to Disqus, by endpoint.",
+		}, []string{"endpoint"}),
+		errors: prom.NewCounterVec(prom.CounterOpts{
+			Name: "gisqus_errors_total",
+			Help: "Number of failed calls made to Disqus, by endpoint.",
+		}, []string{"endpoint"}),
+	}
+	g.Use(c.middleware)
+	return c
+}
+
+// Describe implements prometheus.Collector
+func (c *Collector) Describe(ch chan<- *prom.Desc) {
+
+	ch <- remainingDesc
+	ch <- limitDesc
+	ch <- resetDesc
+	c.requests.Describe(ch)
+	c.errors.Describe(ch)
+}
+
+// Collect implements prometheus.Collector
+func (c *Collector) Collect(ch chan<- prom.Metric) {
+
+	c.mu.Lock()
+	limits, seen := c.limits, c.seen
+	c.mu.Unlock()
+
+	if seen {
+		reset := time.Until(limits.RatelimitReset).Seconds()
+		if reset < 0 {
+			reset = 0
+		}
+		ch <- prom.MustNewConstMetric(remainingDesc, prom.GaugeValue, float64(limits.RatelimitRemaining))
+		ch <- prom.MustNewConstMetric(limitDesc, prom.GaugeValue, float64(limits.RatelimitLimit))
+		ch <- prom.MustNewConstMetric(resetDesc, prom.GaugeValue, reset)
+	}
+	c.requests.Collect(ch)
+	c.errors.Collect(ch)
+}
+
+func (c *Collector) middleware(next gisqus.Doer) gisqus.Doer {
+
+	return gisqus.DoerFunc(func(ctx context.Context, req *gisqus.Request) (*gisqus.Response, error) {
+
+		resp, err := next.Do(ctx, req)
+
+		c.requests.WithLabelValues(req.Endpoint).Inc()
+		if err != nil {
+			c.errors.WithLabelValues(req.Endpoint).Inc()
+		}
+		if resp != nil && resp.Limits.RatelimitLimit > 0 {
+			c.mu.Lock()
+			c.limits = resp.Limits
+			c.seen = true
+			c.mu.Unlock()
+		}
+		return resp, err
+	})
+}
@@ -0,0 +1,76 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package prometheus
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/pierods/gisqus"
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestCollector(t *testing.T) {
+
+	status := 200
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "999")
+		w.Header().Set("X-Ratelimit-Limit", "1000")
+		w.Header().Set("X-Ratelimit-Reset", "1495785600")
+		w.WriteHeader(status)
+		fmt.Fprint(w, `{"code":0,"response":{"id":"1","createdAt":"2017-05-23T17:57:41"}}`)
+	}))
+	defer server.Close()
+
+	g := gisqus.NewGisqus("secret")
+	urls := g.ReadThreadsURLs()
+	urls.ThreadDetailURL = server.URL + "/api/3.0/threads/details.json"
+	g.SetThreadsURLs(urls)
+
+	collector := NewCollector(&g)
+	registry := prom.NewPedanticRegistry()
+	registry.MustRegister(collector)
+
+	if n, err := testutil.GatherAndCount(registry, "gisqus_ratelimit_remaining"); err != nil || n != 0 {
+		t.Fatal("Should not export rate limits before the first call")
+	}
+
+	_, err := g.ThreadDetails(context.Background(), "1", url.Values{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	status = 500
+	_, err = g.ThreadDetails(context.Background(), "1", url.Values{})
+	if err == nil {
+		t.Fatal("Should fail on server errors")
+	}
+
+	expected := `
+# HELP gisqus_errors_total Number of failed calls made to Disqus, by endpoint.
+# TYPE gisqus_errors_total counter
+gisqus_errors_total{endpoint="threads/details"} 1
+# HELP gisqus_ratelimit_limit Disqus rate limit for the account.
+# TYPE gisqus_ratelimit_limit gauge
+gisqus_ratelimit_limit 1000
+# HELP gisqus_ratelimit_remaining Calls left before the Disqus rate limit is hit.
+# TYPE gisqus_ratelimit_remaining gauge
+gisqus_ratelimit_remaining 999
+# HELP gisqus_ratelimit_reset_seconds Seconds until the Disqus rate limit is reset.
+# TYPE gisqus_ratelimit_reset_seconds gauge
+gisqus_ratelimit_reset_seconds 0
+# HELP gisqus_requests_total Number of calls made to Disqus, by endpoint.
+# TYPE gisqus_requests_total counter
+gisqus_requests_total{endpoint="threads/details"} 2
+`
+	err = testutil.GatherAndCompare(registry, strings.NewReader(expected))
+	if err != nil {
+		t.Fatal(err)
+	}
+}
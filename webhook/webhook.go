@@ -0,0 +1,287 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+/*
+Package webhook relays events detected by polling Disqus (by a gisqus.Watcher or a gisqus.ThreadListWatcher) to HTTP
+endpoints, as signed JSON payloads, so that services not written in Go can subscribe to them:
+
+	relay := webhook.NewRelay(webhook.Endpoint{URL: "https://example.com/disqus", Secret: "s3cr3t"})
+	relay.DeadLetter = "undelivered.jsonl"
+	watcher.Handle(relay.PostHandler(ctx))
+	threadWatcher.Handle(relay.ThreadHandler(ctx))
+
+Every payload is POSTed with the headers X-Gisqus-Event (the event type), X-Gisqus-Delivery (a unique id),
+X-Gisqus-Timestamp (unix seconds) and X-Gisqus-Signature, which is "sha256=" followed by the hex encoded HMAC-SHA256 of
+timestamp + "." + body, keyed with the secret of the endpoint (see Verify).
+*/
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+// Header names of deliveries
+const (
+	EventHeader     = "X-Gisqus-Event"
+	DeliveryHeader  = "X-Gisqus-Delivery"
+	TimestampHeader = "X-Gisqus-Timestamp"
+	SignatureHeader = "X-Gisqus-Signature"
+)
+
+// Endpoint models a subscriber. Types restricts the events sent to it (all events if empty).
+type Endpoint struct {
+	URL    string
+	Secret string
+	Types  []string
+}
+
+func (e *Endpoint) accepts(eventType string) bool {
+
+	if len(e.Types) == 0 {
+		return true
+	}
+	for _, t := range e.Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Envelope models the JSON payload sent to endpoints
+type Envelope struct {
+	ID        string          `json:"id"`
+	Type      string          `json:"type"`
+	CreatedAt time.Time       `json:"createdAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// DeadLetter models a delivery that failed for good, as appended to the dead-letter file
+type DeadLetter struct {
+	URL      string    `json:"url"`
+	Envelope *Envelope `json:"envelope"`
+	Attempts int       `json:"attempts"`
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failedAt"`
+}
+
+/*
+Relay POSTs events to its endpoints. Failed deliveries (network errors, 408, 429 and 5xx responses) are retried up to
+MaxAttempts times, waiting Backoff before the first retry and doubling it after every retry; other responses not in the
+2xx range are not retried. Deliveries that fail for good are appended, one JSON object per line, to the DeadLetter
+file, if set.
+*/
+type Relay struct {
+	Endpoints   []Endpoint
+	Client      *http.Client
+	MaxAttempts int
+	Backoff     time.Duration
+	DeadLetter  string
+
+	mu sync.Mutex
+}
+
+// NewRelay returns a Relay making up to 5 attempts per delivery, starting with a 1 second backoff
+func NewRelay(endpoints ...Endpoint) *Relay {
+	return &Relay{
+		Endpoints:   endpoints,
+		Client:      &http.Client{Timeout: 30 * time.Second},
+		MaxAttempts: 5,
+		Backoff:     time.Second,
+	}
+}
+
+/*
+Deliver sends an event to every endpoint accepting eventType, one endpoint after the other. data is marshalled to JSON as
+the data field of the Envelope. Failed deliveries are written to the dead-letter file; an error is returned if some
+could not be written there (or if there is no dead-letter file), or if data cannot be marshalled.
+*/
+func (r *Relay) Deliver(ctx context.Context, eventType string, data interface{}) error {
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	id, err := newID()
+	if err != nil {
+		return err
+	}
+	envelope := &Envelope{
+		ID:        id,
+		Type:      eventType,
+		CreatedAt: time.Now().UTC(),
+		Data:      raw,
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for _, endpoint := range r.Endpoints {
+		if !endpoint.accepts(eventType) {
+			continue
+		}
+		attempts, err := r.send(ctx, &endpoint, envelope, body)
+		if err == nil {
+			continue
+		}
+		err = fmt.Errorf("delivering %s to %s: %w", envelope.ID, endpoint.URL, err)
+		if dlErr := r.deadLetter(endpoint.URL, envelope, attempts, err); dlErr != nil {
+			errs = append(errs, err, dlErr)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+/*
+PostHandler returns a function relaying the events of a gisqus.Watcher, to be registered with Watcher.Handle. Deliveries
+are synchronous, so the Watcher waits for them; errors that cannot be written to the dead-letter file are dropped.
+*/
+func (r *Relay) PostHandler(ctx context.Context) func(*gisqus.PostEvent) {
+	return func(event *gisqus.PostEvent) {
+		r.Deliver(ctx, string(event.Type), event)
+	}
+}
+
+// ThreadHandler is like PostHandler, for the events of a gisqus.ThreadListWatcher
+func (r *Relay) ThreadHandler(ctx context.Context) func(*gisqus.ThreadEvent) {
+	return func(event *gisqus.ThreadEvent) {
+		r.Deliver(ctx, string(event.Type), event)
+	}
+}
+
+func (r *Relay) send(ctx context.Context, endpoint *Endpoint, envelope *Envelope, body []byte) (int, error) {
+
+	backoff := r.Backoff
+	attempt := 1
+	for ; ; attempt++ {
+		retry, err := r.post(ctx, endpoint, envelope, body)
+		if err == nil || !retry || attempt >= r.MaxAttempts {
+			return attempt, err
+		}
+		timer := time.NewTimer(backoff)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return attempt, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// post makes one delivery attempt, and tells whether it is worth retrying if it failed
+func (r *Relay) post(ctx context.Context, endpoint *Endpoint, envelope *Envelope, body []byte) (bool, error) {
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, envelope.Type)
+	req.Header.Set(DeliveryHeader, envelope.ID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(endpoint.Secret, timestamp, body))
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return false, nil
+	case resp.StatusCode == http.StatusRequestTimeout || resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return true, fmt.Errorf("status %d", resp.StatusCode)
+	default:
+		return false, fmt.Errorf("status %d", resp.StatusCode)
+	}
+}
+
+func (r *Relay) deadLetter(url string, envelope *Envelope, attempts int, cause error) error {
+
+	if r.DeadLetter == "" {
+		return errors.New("no dead-letter file")
+	}
+	line, err := json.Marshal(&DeadLetter{
+		URL:      url,
+		Envelope: envelope,
+		Attempts: attempts,
+		Error:    cause.Error(),
+		FailedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, err := os.OpenFile(r.DeadLetter, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(line, '\n'))
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// Sign returns the signature of a payload, as sent in the X-Gisqus-Signature header
+func Sign(secret, timestamp string, body []byte) string {
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+/*
+Verify checks the signature of a payload received by an endpoint, and that its timestamp is no older than maxAge (if
+maxAge > 0), to protect against replays.
+*/
+func Verify(secret, timestamp, signature string, body []byte, maxAge time.Duration) bool {
+
+	if maxAge > 0 {
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil || time.Since(time.Unix(unix, 0)) > maxAge {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(signature), []byte(Sign(secret, timestamp, body)))
+}
+
+func newID() (string, error) {
+
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,137 @@
+// Copyright Piero de Salvia.
+// All Rights Reserved
+
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/pierods/gisqus"
+)
+
+func TestRelay(t *testing.T) {
+
+	var received []*Envelope
+	var flaky, rejected int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/ok", func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !Verify("s3cr3t", r.Header.Get(TimestampHeader), r.Header.Get(SignatureHeader), body, time.Minute) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var envelope Envelope
+		json.Unmarshal(body, &envelope)
+		if r.Header.Get(EventHeader) != envelope.Type || r.Header.Get(DeliveryHeader) != envelope.ID {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		received = append(received, &envelope)
+	})
+	mux.HandleFunc("/flaky", func(w http.ResponseWriter, r *http.Request) {
+		flaky++
+		if flaky < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	})
+	mux.HandleFunc("/rejected", func(w http.ResponseWriter, r *http.Request) {
+		rejected++
+		w.WriteHeader(http.StatusGone)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	relay := NewRelay(
+		Endpoint{URL: server.URL + "/ok", Secret: "s3cr3t"},
+		Endpoint{URL: server.URL + "/flaky", Types: []string{string(gisqus.PostCreated)}},
+		Endpoint{URL: server.URL + "/rejected", Types: []string{string(gisqus.PostDeleted)}},
+	)
+	relay.Backoff = time.Millisecond
+	relay.DeadLetter = filepath.Join(t.TempDir(), "dead.jsonl")
+
+	post := &gisqus.Post{}
+	post.ID = "42"
+	handler := relay.PostHandler(context.Background())
+	handler(&gisqus.PostEvent{Type: gisqus.PostCreated, Post: post})
+	handler(&gisqus.PostEvent{Type: gisqus.PostDeleted, Post: post})
+
+	thread := &gisqus.Thread{ID: "7"}
+	relay.ThreadHandler(context.Background())(&gisqus.ThreadEvent{Type: gisqus.ThreadClosed, Thread: thread})
+
+	if len(received) != 3 || received[0].Type != string(gisqus.PostCreated) || received[0].ID == received[1].ID {
+		t.Fatal("Should deliver signed events")
+	}
+	var event gisqus.PostEvent
+	if json.Unmarshal(received[1].Data, &event) != nil || event.Post.ID != "42" {
+		t.Fatal("Should deliver the event as data")
+	}
+	var threadEvent gisqus.ThreadEvent
+	if received[2].Type != string(gisqus.ThreadClosed) || json.Unmarshal(received[2].Data, &threadEvent) != nil || threadEvent.Thread.ID != "7" {
+		t.Fatal("Should deliver thread events")
+	}
+	if flaky != 3 {
+		t.Fatal("Should retry failed deliveries")
+	}
+	if rejected != 1 {
+		t.Fatal("Should not retry rejected deliveries")
+	}
+
+	f, err := os.Open(relay.DeadLetter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var letters []*DeadLetter
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var letter DeadLetter
+		if json.Unmarshal(scanner.Bytes(), &letter) != nil {
+			t.Fatal("Should write dead letters as JSON lines")
+		}
+		letters = append(letters, &letter)
+	}
+	if len(letters) != 1 || letters[0].URL != server.URL+"/rejected" || letters[0].Attempts != 1 || letters[0].Envelope.Type != string(gisqus.PostDeleted) {
+		t.Fatal("Should write failed deliveries to the dead-letter file")
+	}
+
+	flaky = -10
+	relay.DeadLetter = ""
+	if relay.Deliver(context.Background(), string(gisqus.PostCreated), post) == nil {
+		t.Fatal("Should return an error without a dead-letter file")
+	}
+	if flaky != -5 {
+		t.Fatal("Should stop after MaxAttempts")
+	}
+}
+
+func TestVerify(t *testing.T) {
+
+	body := []byte(`{"id":"1"}`)
+	now := time.Now().Unix()
+	ts := func(unix int64) string { return strconv.FormatInt(unix, 10) }
+
+	signature := Sign("secret", ts(now), body)
+	if !Verify("secret", ts(now), signature, body, time.Minute) {
+		t.Fatal("Should verify valid signatures")
+	}
+	if Verify("other", ts(now), signature, body, time.Minute) || Verify("secret", ts(now), signature, []byte(`{}`), time.Minute) {
+		t.Fatal("Should not verify tampered payloads")
+	}
+	old := ts(now - 3600)
+	if Verify("secret", old, Sign("secret", old, body), body, time.Minute) {
+		t.Fatal("Should not verify stale payloads")
+	}
+	if !Verify("secret", old, Sign("secret", old, body), body, 0) {
+		t.Fatal("Should not check the age of payloads when maxAge is 0")
+	}
+}